@@ -0,0 +1,69 @@
+package easylogger
+
+/*
+ * atomic.go
+ * Keep lines from different loggers apart
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"io"
+	"reflect"
+	"sync"
+)
+
+var (
+	/* writeLocks holds a *sync.Mutex for each writer written to with
+	SetAtomicWrites on. */
+	writeLocks sync.Map
+	/* uncomparableLock is used for writers which can't be map keys */
+	uncomparableLock sync.Mutex
+)
+
+// SetAtomicWrites causes the default LogSet to lock its writers while
+// writing.  See LogSet.SetAtomicWrites.
+func SetAtomicWrites(on bool) { def.SetAtomicWrites(on) }
+
+// SetAtomicWrites causes l to hold a lock for the writer to which it's
+// writing while it writes a message, if on is true.  The lock is shared by
+// every LogSet with SetAtomicWrites on, so lines written to the same writer
+// (e.g. the same *os.File) by LogSets with different loggers, or by both a
+// LogSet and the routes from SetTagRoute, can't interleave, even if the
+// writer doesn't write each call all at once.  Each line is always written
+// with a single call to Write.
+//
+// Locks are kept for the life of the program, one per writer, so this is
+// meant for a handful of long-lived writers.  The same locks are taken,
+// whether or not SetAtomicWrites is on, when a message is written straight
+// to the logger's writer rather than with the logger, as happens with
+// SetFormatFunc, SetJournald, SetUTC and the other settings which change
+// how the logger would write a message.  See SetLogger.
+func (l *LogSet) SetAtomicWrites(on bool) {
+	l.set(func(s *settings) { s.lockW = on })
+}
+
+// lockWriter locks w's lock if SetAtomicWrites is on or direct is true, and
+// returns a function which unlocks it.  direct should be true if w is to be
+// written without going through the logger, whose own lock won't be held.
+func (s *settings) lockWriter(w io.Writer, direct bool) (unlock func()) {
+	if !s.lockW && !direct {
+		return func() {}
+	}
+	m := writeLock(w)
+	m.Lock()
+	return m.Unlock
+}
+
+/* writeLock returns w's lock */
+func writeLock(w io.Writer) *sync.Mutex {
+	if !reflect.ValueOf(w).Comparable() {
+		return &uncomparableLock
+	}
+	if m, ok := writeLocks.Load(w); ok {
+		return m.(*sync.Mutex)
+	}
+	m, _ := writeLocks.LoadOrStore(w, new(sync.Mutex))
+	return m.(*sync.Mutex)
+}
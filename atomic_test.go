@@ -0,0 +1,121 @@
+package easylogger
+
+/*
+ * atomic_test.go
+ * Tests for SetAtomicWrites
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// byteWriter writes a byte at a time, giving other goroutines a chance to
+// write in between, as a writer which doesn't write each call at once
+// might.  It also counts calls to Write which weren't exactly one line.
+type byteWriter struct {
+	m       sync.Mutex
+	b       bytes.Buffer
+	partial int /* Calls to Write without exactly one line */
+}
+
+/* Write writes p a byte at a time */
+func (w *byteWriter) Write(p []byte) (int, error) {
+	if 1 != bytes.Count(p, []byte("\n")) || '\n' != p[len(p)-1] {
+		w.m.Lock()
+		w.partial++
+		w.m.Unlock()
+	}
+	for _, c := range p {
+		w.m.Lock()
+		w.b.WriteByte(c)
+		w.m.Unlock()
+		runtime.Gosched()
+	}
+	return len(p), nil
+}
+
+func TestAtomicWritesNoSplitLines(t *testing.T) {
+	const (
+		nSets  = 4
+		nLines = 200
+	)
+	var (
+		w  byteWriter
+		wg sync.WaitGroup
+	)
+	/* Separate loggers, so only SetAtomicWrites keeps them apart */
+	for i := 0; i < nSets; i++ {
+		l := New()
+		l.SetLogger(log.New(&w, "", 0))
+		l.LogDebug()
+		l.SetAtomicWrites(true)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < nLines; j++ {
+				l.Debug("set=%d line=%d %s", i, j, strings.Repeat("x", j))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if 0 != w.partial {
+		t.Errorf("%d writes weren't exactly one line", w.partial)
+	}
+	/* Every line should be whole */
+	lines := strings.Split(strings.TrimSuffix(w.b.String(), "\n"), "\n")
+	if nSets*nLines != len(lines) {
+		t.Fatalf("Got %d lines, want %d", len(lines), nSets*nLines)
+	}
+	seen := make(map[string]bool)
+	for _, line := range lines {
+		var i, j int
+		if _, err := fmt.Sscanf(line, "set=%d line=%d ", &i, &j); nil != err {
+			t.Fatalf("Split line %q: %v", line, err)
+		}
+		want := fmt.Sprintf("set=%d line=%d %s", i, j, strings.Repeat("x", j))
+		if line != want {
+			t.Fatalf("Split line %q", line)
+		}
+		seen[line] = true
+	}
+	if nSets*nLines != len(seen) {
+		t.Errorf("Got %d different lines, want %d", len(seen), nSets*nLines)
+	}
+}
+
+func TestDirectWritesLocked(t *testing.T) {
+	var (
+		b  bytes.Buffer /* Not safe for concurrent use */
+		wg sync.WaitGroup
+	)
+	for _, set := range []func(l *LogSet){
+		func(l *LogSet) { l.SetUTC(true) },
+		func(l *LogSet) { l.SetJournald(true) },
+		func(l *LogSet) { l.SetFormatFunc(BinaryFormat) },
+		func(l *LogSet) { l.SetLineTerminator("\r\n") },
+	} {
+		/* Each with its own logger, so only our lock keeps them apart */
+		l := New()
+		l.SetLogger(log.New(&b, "", log.LstdFlags))
+		l.LogDebug()
+		set(l)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				l.Debug("message %d", i)
+			}
+		}()
+	}
+	wg.Wait()
+}
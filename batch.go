@@ -0,0 +1,68 @@
+package easylogger
+
+/*
+ * batch.go
+ * Log several messages together
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+// A Batch collects messages to be logged together by LogSet.Batch.
+type Batch struct {
+	l       *LogSet
+	records []Record
+}
+
+// Verbose adds a verbose message to the batch, if verbose messages are
+// logged.
+func (b *Batch) Verbose(format string, args ...interface{}) {
+	b.add(LevelVerbose, format, args...)
+}
+
+// Debug adds a debugging message to the batch, if debugging messages are
+// logged.
+func (b *Batch) Debug(format string, args ...interface{}) {
+	b.add(LevelDebug, format, args...)
+}
+
+/* add adds a message to the batch, if messages at level are logged */
+func (b *Batch) add(level Level, format string, args ...interface{}) {
+	/* Multi LogSets can't batch across children */
+	if 0 != len(b.l.children) {
+		b.l.log(level, format, args...)
+		return
+	}
+	level, ok := b.l.gate(level)
+	if !ok {
+		return
+	}
+	if r := b.l.record(level, nil, format, args...); !r.Drop {
+		b.records = append(b.records, r)
+	}
+}
+
+// Batch runs fn, and then logs the messages added to b by fn all at once,
+// so that they're not interleaved with messages logged with l by other
+// goroutines.  Whether each message is logged is decided when it's added.
+//
+//    ls.Batch(func(b *easylogger.Batch) {
+//            b.Verbose("Request from %v", addr)
+//            b.Debug("Headers: %v", headers)
+//    })
+//
+// l's lock is held while the messages are written, as with any message, so
+// large batches will hold up logging in other goroutines.
+func (l *LogSet) Batch(fn func(b *Batch)) {
+	l.check()
+	b := &Batch{l: l}
+	fn(b)
+	l.m.Lock()
+	for _, r := range b.records {
+		l.write(r)
+	}
+	l.m.Unlock()
+	for _, r := range b.records {
+		l.notify(r)
+	}
+}
@@ -0,0 +1,99 @@
+package easylogger
+
+/*
+ * bench_test.go
+ * Benchmarks
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"testing"
+)
+
+/* discardSet returns a LogSet which logs everything to io.Discard */
+func discardSet() *LogSet {
+	l := New()
+	l.SetLogger(log.New(io.Discard, "", log.LstdFlags))
+	l.LogDebug()
+	return l
+}
+
+// BenchmarkParallelDisabled measures checking whether to log from many
+// goroutines, which reads the switches without a lock.
+func BenchmarkParallelDisabled(b *testing.B) {
+	l := discardSet()
+	l.LogNone()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Debug("Not logged %d", 1)
+		}
+	})
+}
+
+// BenchmarkParallelEnabled measures logging from many goroutines, which
+// reads the switches, logger and settings without a lock.
+func BenchmarkParallelEnabled(b *testing.B) {
+	l := discardSet()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Debug("Logged %d", 1)
+		}
+	})
+}
+
+// BenchmarkTextNoFields measures logging a plain text message, the common
+// case.
+func BenchmarkTextNoFields(b *testing.B) {
+	l := discardSet()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Debug("Logged %d", i)
+	}
+}
+
+// BenchmarkTextWithFields measures logging a text message with a sequence
+// number, host, pid, and tags.
+func BenchmarkTextWithFields(b *testing.B) {
+	l := discardSet()
+	l.SetSequence(true)
+	l.SetHostField(true)
+	l.SetPIDField(true)
+	tags := []string{"db", "slow"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.DebugTagged(tags, "Logged %d", i)
+	}
+}
+
+// BenchmarkJSON measures logging a message rendered as JSON by the function
+// passed to SetFormatFunc.
+func BenchmarkJSON(b *testing.B) {
+	l := discardSet()
+	l.SetFormatFunc(func(r Record) []byte {
+		j, err := json.Marshal(r)
+		if nil != err {
+			b.Fatalf("Marshal: %v", err)
+		}
+		return append(j, '\n')
+	})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Debug("Logged %d", i)
+	}
+}
+
+// BenchmarkDisabled measures not logging a message because its level is
+// turned off.
+func BenchmarkDisabled(b *testing.B) {
+	l := discardSet()
+	l.LogVerbose()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Debug("Not logged %d", i)
+	}
+}
@@ -0,0 +1,83 @@
+package easylogger
+
+/*
+ * binary.go
+ * Length-prefixed binary records
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// maxBinaryRecord is the largest record, not counting its length, which
+// BinaryFormat will write and DecodeRecord will read, to avoid huge
+// allocations on corrupt input.
+const maxBinaryRecord = 16 << 20
+
+// BinaryFormat renders r in a compact binary form, for use with
+// SetFormatFunc when shipping logs to something which reads them with
+// DecodeRecord:
+//
+//    ls.SetFormatFunc(easylogger.BinaryFormat)
+//
+// The wire format, with all integers big-endian, is
+//
+//    length   uint32  Number of bytes which follow
+//    time     int64   r.Time, in nanoseconds since the Unix epoch
+//    level    uint8   r.Level
+//    message  []byte  r.Message, to the end of the record
+//
+// No newline or other separator is written between records.  r.Tags aren't
+// written, so records read by DecodeRecord have none.  A record may be at
+// most 16MiB long, not counting its length; longer messages are cut short
+// and marked as with SetMaxMessageLen, so that DecodeRecord can read them.
+func BinaryFormat(r Record) []byte {
+	msg := r.Message
+	if room := maxBinaryRecord - 8 - 1; len(msg) > room {
+		msg = truncate(msg, room)
+	}
+	b := make([]byte, 4+8+1+len(msg))
+	binary.BigEndian.PutUint32(b, uint32(len(b)-4))
+	binary.BigEndian.PutUint64(b[4:], uint64(r.Time.UnixNano()))
+	b[12] = byte(r.Level)
+	copy(b[13:], msg)
+	return b
+}
+
+// DecodeRecord reads a record written by BinaryFormat from r.  It returns
+// io.EOF if there are no more records, and io.ErrUnexpectedEOF if r ends
+// partway through a record.
+func DecodeRecord(r io.Reader) (Record, error) {
+	var hdr [4]byte
+	/* Length, which may be the end */
+	if _, err := io.ReadFull(r, hdr[:]); nil != err {
+		return Record{}, err
+	}
+	n := binary.BigEndian.Uint32(hdr[:])
+	if n < 8+1 {
+		return Record{}, fmt.Errorf("easylogger: record length %d too short", n)
+	}
+	if n > maxBinaryRecord {
+		return Record{}, fmt.Errorf("easylogger: record length %d too long", n)
+	}
+	/* The rest of it */
+	rest := make([]byte, n)
+	if _, err := io.ReadFull(r, rest); nil != err {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return Record{}, err
+	}
+	return Record{
+		Time:    time.Unix(0, int64(binary.BigEndian.Uint64(rest))),
+		Level:   Level(rest[8]),
+		Message: string(rest[9:]),
+	}, nil
+}
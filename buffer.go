@@ -0,0 +1,141 @@
+package easylogger
+
+/*
+ * buffer.go
+ * Buffered log output
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"bufio"
+	"log"
+	"sync"
+	"time"
+)
+
+// bufWriter is a bufio.Writer which may be flushed from another goroutine.
+type bufWriter struct {
+	m    sync.Mutex
+	b    *bufio.Writer
+	own  *log.Logger   /* Logger writing to the buffer */
+	prev *log.Logger   /* Logger in use before buffering */
+	done chan struct{} /* Closed to stop periodic flushing */
+}
+
+/* Write writes p to the buffer */
+func (w *bufWriter) Write(p []byte) (int, error) {
+	w.m.Lock()
+	defer w.m.Unlock()
+	return w.b.Write(p)
+}
+
+/* Flush writes any buffered data to the underlying writer */
+func (w *bufWriter) Flush() error {
+	w.m.Lock()
+	defer w.m.Unlock()
+	return w.b.Flush()
+}
+
+/* flushEvery flushes w every d until w.done is closed */
+func (w *bufWriter) flushEvery(d time.Duration) {
+	t := time.NewTicker(d)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			w.Flush()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// SetBuffered buffers the default LogSet's output.  See LogSet.SetBuffered.
+func SetBuffered(size int, flushEvery time.Duration) {
+	def.SetBuffered(size, flushEvery)
+}
+
+// Flush writes any buffered output from the default LogSet.
+func Flush() error { return def.Flush() }
+
+// Close stops the default LogSet's heartbeats, flushes and stops buffering
+// its output, and closes its remote connection.
+func Close() error { return def.Close() }
+
+// SetBuffered wraps the output of the logger in use (the standard logger,
+// unless SetLogger has been called) in a bufio.Writer of the given size, to
+// save a syscall per message when logging to a file.  The logger itself isn't
+// changed; l logs to a copy of it, with the same prefix and flags, until
+// buffering is turned off, so changes made to the logger in the meantime
+// aren't seen by l.  Messages are still
+// formatted when they're logged, but are only written when the buffer fills,
+// every flushEvery (if it's positive), or when Flush or Close is called.
+// Panics logged by RecoverAndLog and RecoverAndContinue are always flushed
+// immediately.
+//
+// Buffering trades durability for speed: anything still buffered when the
+// program exits without calling Flush or Close, or crashes without a logged
+// panic, is lost.
+//
+// Calling SetBuffered again flushes and replaces the previous buffer.  A size
+// of 0 or less turns buffering off.
+func (l *LogSet) SetBuffered(size int, flushEvery time.Duration) {
+	/* Get rid of the old buffer */
+	l.unbuffer()
+	if 0 >= size {
+		return
+	}
+	/* Log to a buffered copy of the current logger */
+	t := l.target()
+	w := &bufWriter{
+		b:    bufio.NewWriterSize(t.Writer(), size),
+		prev: l.logger.Load(),
+		done: make(chan struct{}),
+	}
+	w.own = log.New(w, t.Prefix(), t.Flags())
+	l.logger.Store(w.own)
+	if 0 < flushEvery {
+		go w.flushEvery(flushEvery)
+	}
+	l.buf.Store(w)
+}
+
+// Flush writes any output buffered by SetBuffered.  It is a no-op if output
+// isn't buffered.
+func (l *LogSet) Flush() error {
+	w := l.buf.Load()
+	if nil == w {
+		return nil
+	}
+	return w.Flush()
+}
+
+// Close stops heartbeats started with StartHeartbeat, flushes buffered
+// output and turns buffering off, closes the connection made by SetRemote,
+// and stops sending messages to the Windows Event Log.  The LogSet may still
+// be used afterwards, though messages sent to a closed remote are lost.
+func (l *LogSet) Close() error {
+	l.stopHeartbeats()
+	err := l.unbuffer()
+	if rerr := l.closeRemote(); nil == err {
+		err = rerr
+	}
+	if eerr := l.closeEventLog(); nil == err {
+		err = eerr
+	}
+	return err
+}
+
+// unbuffer stops buffering, flushes the buffer, and puts back the original
+// logger, unless SetLogger has been called since.
+func (l *LogSet) unbuffer() error {
+	w := l.buf.Swap(nil)
+	if nil == w {
+		return nil
+	}
+	close(w.done)
+	l.logger.CompareAndSwap(w.own, w.prev)
+	return w.Flush()
+}
@@ -0,0 +1,148 @@
+package easylogger
+
+/*
+ * caller.go
+ * Work out who logged a message
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"errors"
+	"log"
+	"path"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// SetCallerTrim causes the default LogSet to trim prefix from file names.
+// See LogSet.SetCallerTrim.
+func SetCallerTrim(prefix string) { def.SetCallerTrim(prefix) }
+
+// SetCallerTrim causes prefix to be trimmed from the start of the file names
+// logged because of log.Llongfile, so that a file name such as
+// /home/build/src/app/db/conn.go becomes db/conn.go with a prefix of
+// /home/build/src/app/.  It has no effect with log.Lshortfile, which logs
+// only the file's base name.  An empty prefix, the default, logs file names
+// in full.  Building with go build -trimpath is another way to get rid of
+// the build machine's directories.
+func (l *LogSet) SetCallerTrim(prefix string) {
+	l.set(func(s *settings) { s.trim = prefix })
+}
+
+// SetCallerTrimModule causes the default LogSet to trim the main module's
+// directory from file names.  See LogSet.SetCallerTrimModule.
+func SetCallerTrimModule() error { return def.setCallerTrimModule() }
+
+// SetCallerTrimModule is like SetCallerTrim, but works out the prefix
+// itself, so that file names in the main module are logged relative to the
+// module's root, e.g. db/conn.go.  The root is found from the build info's
+// module path and the file names of the functions which called
+// SetCallerTrimModule, so it should be called from the main module, such as
+// from main.  File names outside the main module are logged in full.  An
+// error is returned, and nothing's changed, if the root can't be found, as
+// when built without module support or called only from other modules.
+func (l *LogSet) SetCallerTrimModule() error { return l.setCallerTrimModule() }
+
+// setCallerTrimModule does the work for both SetCallerTrimModule functions,
+// which must call it directly so the stack is as moduleRoot expects.
+func (l *LogSet) setCallerTrimModule() error {
+	root, err := moduleRoot()
+	if nil != err {
+		return err
+	}
+	l.SetCallerTrim(root)
+	return nil
+}
+
+// moduleRoot returns the directory holding the main module, with a trailing
+// slash, as it appears in file names from runtime.  It looks for a function
+// in the main module among the callers of SetCallerTrimModule and takes the
+// function's package's directory within the module off its file's
+// directory.
+func moduleRoot() (string, error) {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok || "" == bi.Main.Path {
+		return "", errors.New("easylogger: no main module")
+	}
+	pcs := make([]uintptr, maxCallDepth)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(4, pcs)])
+	for {
+		f, more := frames.Next()
+		/* Work out the function's package; main's is in the build info */
+		pkg := bi.Path
+		if !strings.HasPrefix(f.Function, "main.") {
+			i := strings.LastIndex(f.Function, "/") + 1
+			j := strings.Index(f.Function[i:], ".")
+			if 0 > j {
+				j = len(f.Function) - i
+			}
+			pkg = f.Function[:i+j]
+		}
+		/* If it's in the module, its directory tells us the root */
+		if rel, ok := strings.CutPrefix(pkg, bi.Main.Path); ok &&
+			("" == rel || strings.HasPrefix(rel, "/")) {
+			if dir := path.Dir(f.File); strings.HasSuffix(dir, rel) {
+				return strings.TrimSuffix(dir, rel) + "/", nil
+			}
+		}
+		if !more {
+			return "", errors.New(
+				"easylogger: no caller in main module " + bi.Main.Path,
+			)
+		}
+	}
+}
+
+// maxCallDepth is the deepest callDepth looks for a caller.
+const maxCallDepth = 64
+
+// pkgPrefix is the prefix of the names of this package's functions, e.g.
+// github.com/kd5pbo/easylogger.
+var pkgPrefix = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	name := runtime.FuncForPC(pc).Name()
+	i := strings.LastIndex(name, "/") + 1
+	return name[:i+strings.Index(name[i:], ".")+1]
+}()
+
+// callDepth returns the calldepth to pass to log.Logger.Output, when called
+// by the function calling Output, so that log.Lshortfile and log.Llongfile
+// show where the message was logged.  Frames in this package are skipped,
+// whether the message was logged with a LogSet method or a top-level
+// function, as are frames in log and log/slog for messages from
+// CaptureStdLog and InstallAsSlogDefault, and in runtime, so panics logged
+// by RecoverAndLog show where the panic happened.  Frames in this package's
+// tests are callers like any other.
+func callDepth() int {
+	pcs := make([]uintptr, maxCallDepth)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for depth := 1; ; depth++ {
+		f, more := frames.Next()
+		if !internalFrame(f.Function) ||
+			strings.HasSuffix(f.File, "_test.go") || !more {
+			return depth
+		}
+	}
+}
+
+// internalFrame returns true if fn is in this package, log, log/slog, or
+// runtime.
+func internalFrame(fn string) bool {
+	return strings.HasPrefix(fn, pkgPrefix) ||
+		strings.HasPrefix(fn, "log.") ||
+		strings.HasPrefix(fn, "log/slog.") ||
+		strings.HasPrefix(fn, "runtime.")
+}
+
+// printCaller writes msg to t, with the caller's file and line if t's flags
+// call for them.
+func printCaller(t *log.Logger, msg string) {
+	if 0 == t.Flags()&(log.Lshortfile|log.Llongfile) {
+		t.Output(1, msg)
+		return
+	}
+	t.Output(callDepth(), msg)
+}
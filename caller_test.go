@@ -0,0 +1,73 @@
+package easylogger
+
+/*
+ * caller_test.go
+ * Tests for working out who logged a message
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"path"
+	"runtime"
+	"testing"
+)
+
+// above returns the file:line of the line above the one from which it's
+// called, as log.Lshortfile would log it.
+func above() string {
+	_, file, line, _ := runtime.Caller(1)
+	return fmt.Sprintf("%s:%d", path.Base(file), line-1)
+}
+
+func TestCallerTopLevelAndMethod(t *testing.T) {
+	var b bytes.Buffer
+	defer Reset()
+	Reset()
+	SetLogger(log.New(&b, "", log.Lshortfile))
+	LogDebug()
+	verbose, debug := Generate(false)
+	l := New()
+	l.SetLogger(log.New(&b, "", log.Lshortfile))
+	l.LogDebug()
+
+	for _, c := range []struct {
+		name string
+		log  func() string
+	}{
+		{"Generate verbose", func() string {
+			verbose("message")
+			return above()
+		}},
+		{"Generate debug", func() string {
+			debug("message")
+			return above()
+		}},
+		{"DebugOnceCaller", func() string {
+			DebugOnceCaller("message")
+			return above()
+		}},
+		{"LogSet.Verbose", func() string {
+			l.Verbose("message")
+			return above()
+		}},
+		{"LogSet.Debug", func() string {
+			l.Debug("message")
+			return above()
+		}},
+		{"LogSet.DebugOnceCaller", func() string {
+			l.DebugOnceCaller("message")
+			return above()
+		}},
+	} {
+		b.Reset()
+		want := c.log() + ": message\n"
+		if got := b.String(); got != want {
+			t.Errorf("%s: got %q, want %q", c.name, got, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package easylogger
+
+/*
+ * capture.go
+ * Capture log output
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"bytes"
+	"log"
+	"strings"
+)
+
+// Capture captures the default LogSet's output while fn runs.  See
+// LogSet.Capture.
+func Capture(fn func()) []string { return def.Capture(fn) }
+
+// Capture runs fn and returns the lines logged by l while it ran, without
+// timestamps, the logger's prefix, or the prefix from Apply.  Fields added to
+// messages, such as those from SetSequence or SetPrefixFunc, are kept.
+// Nothing is written to l's usual output in the meantime.  The usual output
+// and prefix are put back when fn returns, even if it panics.
+//
+//    lines := ls.Capture(func() {
+//            ls.LogVerbose()
+//            ls.Verbose("Hello, %s", "world")
+//    })
+//    /* lines is []string{"Hello, world"} */
+//
+// Capture isn't safe for concurrent use: output from other goroutines using
+// l will be captured as well, and calling SetLogger during the capture will
+// have no lasting effect.  Output from the function passed to SetFormatFunc,
+// such as BinaryFormat, is split at newlines like any other, which isn't
+// likely to be useful.
+func (l *LogSet) Capture(fn func()) []string {
+	var b bytes.Buffer
+	/* Swap in the buffer and lose the prefix until fn's done */
+	var prefix *string
+	l.set(func(s *settings) { prefix, s.prefix = s.prefix, nil })
+	old := l.logger.Swap(log.New(&b, "", 0))
+	func() {
+		defer func() {
+			l.logger.Store(old)
+			/* Unless fn set another */
+			l.set(func(s *settings) {
+				if nil == s.prefix {
+					s.prefix = prefix
+				}
+			})
+		}()
+		fn()
+	}()
+	if 0 == b.Len() {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+}
@@ -0,0 +1,229 @@
+package easylogger
+
+/*
+ * config.go
+ * Compare LogSets' configuration
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync/atomic"
+)
+
+// config is a snapshot of the parts of a LogSet's configuration which
+// affect what's logged and how it looks.
+type config struct {
+	verbose    bool        /* Verbose messages are logged */
+	debug      bool        /* Debug messages are logged */
+	prefix     string      /* Put before messages */
+	flags      int         /* Logger's flags, and -v -v -v's */
+	promote    int32       /* From SetDebugPromotion */
+	quiet      quietWindow /* From SetQuietWindow */
+	journald   bool        /* From SetJournald */
+	sequence   bool        /* From SetSequence */
+	host       string      /* From SetHostField */
+	pid        string      /* From SetPIDField */
+	maxLen     int         /* From SetMaxMessageLen */
+	escape     bool        /* From SetEscapeNewlines */
+	uptime     bool        /* From SetUptimeField */
+	errStack   bool        /* From SetExtractErrorStack */
+	wrap       bool        /* From SetWrap */
+	timezone   string      /* From SetTimezone or SetUTC */
+	terminator string      /* From SetLineTerminator */
+	trim       string      /* From SetCallerTrim */
+	transform  bool        /* SetMessageTransform was given a function */
+	format     bool        /* SetFormatFunc was given a function */
+	prefixFunc bool        /* SetPrefixFunc was given a function */
+}
+
+/* snapshot returns a snapshot of l's configuration */
+func (l *LogSet) snapshot() config {
+	v, d, c := l.switches()
+	t := l.target()
+	s := l.conf()
+	cf := config{
+		verbose:    v || (!c && d), /* See Verbose */
+		debug:      d,
+		prefix:     s.prefixOf(t),
+		flags:      t.Flags(),
+		promote:    l.promote.Load(),
+		journald:   s.journald,
+		sequence:   s.seqOn,
+		host:       s.hostField,
+		pid:        s.pidField,
+		maxLen:     s.maxLen,
+		escape:     s.escapeNL,
+		uptime:     s.uptimeOn,
+		errStack:   s.errStack,
+		wrap:       s.wrap,
+		terminator: s.lineTerm(),
+		trim:       s.trim,
+		transform:  nil != s.transform,
+		format:     nil != s.format,
+		prefixFunc: nil != s.prefixFn,
+	}
+	if s.shortfile && 0 == cf.flags&(log.Lshortfile|log.Llongfile) {
+		cf.flags |= log.Lshortfile
+	}
+	if nil != s.tz {
+		cf.timezone = s.tz.String()
+	}
+	if q := l.quiet.Load(); nil != q {
+		cf.quiet = *q
+	}
+	return cf
+}
+
+// ConfigEqual reports whether l and other log the same messages in the same
+// way.  This compares whether verbose and debug messages are logged, the
+// prefix and flags of the logger in use, and what's been set with
+// SetJournald, SetSequence, SetHostField, SetMaxMessageLen, SetTimezone,
+// SetLineTerminator, SetQuietWindow, and the other functions which change
+// how messages look, but not where output is written.  Functions, such as
+// those given to SetFormatFunc and SetMessageTransform, are only compared
+// by whether they're set, as Go can't tell whether two functions are the
+// same.
+func (l *LogSet) ConfigEqual(other *LogSet) bool {
+	return l.snapshot() == other.snapshot()
+}
+
+// ConfigDiff returns a description of each difference between l's and
+// other's configuration, or nil if ConfigEqual would return true.  Each
+// difference is of the form
+//
+//    name: l's value != other's value
+func (l *LogSet) ConfigDiff(other *LogSet) []string {
+	a, b := l.snapshot(), other.snapshot()
+	var diffs []string
+	add := func(name string, av, bv interface{}) {
+		if av != bv {
+			diffs = append(diffs, fmt.Sprintf("%s: %v != %v", name, av, bv))
+		}
+	}
+	add("verbose", a.verbose, b.verbose)
+	add("debug", a.debug, b.debug)
+	add("prefix", fmt.Sprintf("%q", a.prefix), fmt.Sprintf("%q", b.prefix))
+	add("flags", a.flags, b.flags)
+	add("promote", a.promote, b.promote)
+	add(
+		"quiet",
+		fmt.Sprintf("%v-%v", a.quiet.start, a.quiet.end),
+		fmt.Sprintf("%v-%v", b.quiet.start, b.quiet.end),
+	)
+	add("journald", a.journald, b.journald)
+	add("sequence", a.sequence, b.sequence)
+	add("host", fmt.Sprintf("%q", a.host), fmt.Sprintf("%q", b.host))
+	add("pid", fmt.Sprintf("%q", a.pid), fmt.Sprintf("%q", b.pid))
+	add("maxlen", a.maxLen, b.maxLen)
+	add("escape", a.escape, b.escape)
+	add("uptime", a.uptime, b.uptime)
+	add("errstack", a.errStack, b.errStack)
+	add("wrap", a.wrap, b.wrap)
+	add("timezone", fmt.Sprintf("%q", a.timezone), fmt.Sprintf("%q", b.timezone))
+	add(
+		"terminator",
+		fmt.Sprintf("%q", a.terminator),
+		fmt.Sprintf("%q", b.terminator),
+	)
+	add("trim", fmt.Sprintf("%q", a.trim), fmt.Sprintf("%q", b.trim))
+	add("transform", a.transform, b.transform)
+	add("format", a.format, b.format)
+	add("prefixfunc", a.prefixFunc, b.prefixFunc)
+	return diffs
+}
+
+// Config is a LogSet's configuration, as might be read from a config file.
+// Zero values leave the corresponding setting as it is, so booleans can only
+// turn things on.
+type Config struct {
+	Level          string `json:"level"`           /* See ParseLevel */
+	Prefix         string `json:"prefix"`          /* Put before messages */
+	UTC            bool   `json:"utc"`             /* See SetUTC */
+	EscapeNewlines bool   `json:"escape_newlines"` /* See SetEscapeNewlines */
+	Sequence       bool   `json:"sequence"`        /* See SetSequence */
+	Host           bool   `json:"host"`            /* See SetHostField */
+	PID            bool   `json:"pid"`             /* See SetPIDField */
+	Journald       bool   `json:"journald"`        /* See SetJournald */
+	MaxMessageLen  int    `json:"max_message_len"` /* See SetMaxMessageLen */
+}
+
+// strictConfig is set by SetStrictConfig.
+var strictConfig atomic.Bool
+
+// SetStrictConfig turns strict config parsing on or off.  With it on,
+// ConfigureFromJSON rejects keys which aren't in Config, and
+// ConfigureFromEnv rejects variables with its prefix which aren't for a
+// field of Config, which catches misspelt settings.  It's off by default, so
+// configuration meant for newer versions is ignored.  It's separate from
+// SetStrictInit, so that checking for LogSets not made with New doesn't
+// change how configuration is parsed.
+func SetStrictConfig(strict bool) { strictConfig.Store(strict) }
+
+// ConfigureFromJSON configures the default LogSet from JSON.  See
+// LogSet.ConfigureFromJSON.
+func ConfigureFromJSON(data []byte) error { return def.ConfigureFromJSON(data) }
+
+// ConfigureFromJSON unmarshals data, a JSON object, into a Config and
+// applies it with Apply, e.g.
+//
+//    {"level": "debug", "prefix": "app: ", "utc": true}
+//
+// If SetStrictConfig has turned strict parsing on, keys not in Config cause
+// an error.  Otherwise, they're ignored.
+func (l *LogSet) ConfigureFromJSON(data []byte) error {
+	var c Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if strictConfig.Load() {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(&c); nil != err {
+		return fmt.Errorf("easylogger: parsing config: %w", err)
+	}
+	return l.Apply(c)
+}
+
+// Apply applies c to l.  An error is returned, and nothing's changed, if
+// c.Level isn't a valid level.  The prefix is only used for l's messages;
+// the logger's own prefix isn't changed.
+func (l *LogSet) Apply(c Config) error {
+	/* Make sure the level's ok before changing anything */
+	if "" != c.Level {
+		level, err := ParseLevel(c.Level)
+		if nil != err {
+			return err
+		}
+		l.SetLevel(level)
+	}
+	if "" != c.Prefix {
+		prefix := c.Prefix
+		l.set(func(s *settings) { s.prefix = &prefix })
+	}
+	if c.UTC {
+		l.SetUTC(true)
+	}
+	if c.EscapeNewlines {
+		l.SetEscapeNewlines(true)
+	}
+	if c.Sequence {
+		l.SetSequence(true)
+	}
+	if c.Host {
+		l.SetHostField(true)
+	}
+	if c.PID {
+		l.SetPIDField(true)
+	}
+	if c.Journald {
+		l.SetJournald(true)
+	}
+	if 0 != c.MaxMessageLen {
+		l.SetMaxMessageLen(c.MaxMessageLen)
+	}
+	return nil
+}
@@ -53,9 +53,18 @@
 package easylogger
 
 import (
-	"flag"
+	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
 )
 
 /*
@@ -95,11 +104,28 @@ import (
  */
 
 var (
+	// processStart is roughly when the program started.
+	processStart = time.Now()
+
+	// strictInit is set by SetStrictInit.
+	strictInit atomic.Bool
+
+	// newlineEscaper escapes newlines for SetEscapeNewlines.
+	newlineEscaper = strings.NewReplacer("\n", `\n`, "\r", `\r`)
+
 	// def is the default LogSet used when the top-level functions (which
 	// are wrappers for L's methods and variables) are called.
-	def = new(LogSet)
+	def = New()
 )
 
+// Default returns the default LogSet, which is used by the top-level
+// functions.  Changes made to the returned LogSet affect subsequent calls to
+// the top-level functions, and to the functions returned by Generate, and
+// vice versa.
+//
+//    easylogger.Default().SetMaxMessageLen(1024)
+func Default() *LogSet { return def }
+
 // Generate verbose and debug functions.
 //
 // If makeFlags is true, the
@@ -114,11 +140,9 @@ func Generate(makeFlags bool) (verbose,
 	debug func(format string, args ...interface{})) {
 	/* Set flags if we're meant to */
 	if makeFlags {
-		def.verboseOn = flag.Bool("verbose", false, "Log verbosely")
-		def.debugOn = flag.Bool("debug", false, "Log debugging messages")
+		return GenerateOpts(WithFlags())
 	}
-
-	return def.Verbose, def.Debug
+	return GenerateOpts()
 }
 
 // SetLogger causes l to be used for log output.  This may be nil to use the
@@ -127,6 +151,47 @@ func SetLogger(l *log.Logger) {
 	def.SetLogger(l)
 }
 
+// SetMessageTransform causes f to be called on every message from the
+// default LogSet before it's logged.  See LogSet.SetMessageTransform.
+func SetMessageTransform(f func(string) string) { def.SetMessageTransform(f) }
+
+// SetEscapeNewlines causes newlines in messages from the default LogSet to be
+// escaped if escape is true.  See LogSet.SetEscapeNewlines.
+func SetEscapeNewlines(escape bool) { def.SetEscapeNewlines(escape) }
+
+// SetSequence causes messages from the default LogSet to be prefixed with
+// sequence numbers if on is true.  See LogSet.SetSequence.
+func SetSequence(on bool) { def.SetSequence(on) }
+
+// SetFormatFunc causes f to be used to render messages from the default
+// LogSet.  See LogSet.SetFormatFunc.
+func SetFormatFunc(f func(Record) []byte) { def.SetFormatFunc(f) }
+
+// SetHostField causes messages from the default LogSet to be prefixed with
+// the hostname if on is true.  See LogSet.SetHostField.
+func SetHostField(on bool) { def.SetHostField(on) }
+
+// SetUptimeField causes messages from the default LogSet to be prefixed with
+// the time since the program started if on is true.  See
+// LogSet.SetUptimeField.
+func SetUptimeField(on bool) { def.SetUptimeField(on) }
+
+// SetPIDField causes messages from the default LogSet to be prefixed with
+// the process ID if on is true.  See LogSet.SetPIDField.
+func SetPIDField(on bool) { def.SetPIDField(on) }
+
+// SetPrefixFunc causes f to be called to get a prefix for every message from
+// the default LogSet.  See LogSet.SetPrefixFunc.
+func SetPrefixFunc(f func() string) { def.SetPrefixFunc(f) }
+
+// SetMaxMessageLen limits the length of messages from the default LogSet.
+// See LogSet.SetMaxMessageLen.
+func SetMaxMessageLen(n int) { def.SetMaxMessageLen(n) }
+
+// SetUTC causes timestamps to be in UTC if utc is true, or in local time if
+// utc is false.  See LogSet.SetUTC.
+func SetUTC(utc bool) { def.SetUTC(utc) }
+
 // LogVerbose turns on Verbose logging
 // (verbose will log messages, debug won't).
 func LogVerbose() { def.LogVerbose() }
@@ -165,77 +230,469 @@ func Resume() {
 	def.Resume()
 }
 
+// VerboseIf logs a verbose message from the default LogSet if cond is true.
+// See LogSet.VerboseIf.
+func VerboseIf(cond bool, format string, args ...interface{}) {
+	def.VerboseIf(cond, format, args...)
+}
+
+// DebugIf logs a debugging message from the default LogSet if cond is true.
+// See LogSet.DebugIf.
+func DebugIf(cond bool, format string, args ...interface{}) {
+	def.DebugIf(cond, format, args...)
+}
+
+// Log logs a message at the given level from the default LogSet.  See
+// LogSet.Log.
+func Log(level Level, format string, args ...interface{}) {
+	def.Log(level, format, args...)
+}
+
+// RecoverAndLog recovers from a panic, logs it with a stack trace, and
+// panics again.  It must be deferred directly.  See LogSet.RecoverAndLog.
+func RecoverAndLog() { def.logPanic(recover(), true) }
+
+// RecoverAndContinue recovers from a panic and logs it with a stack trace,
+// but does not panic again.  It must be deferred directly.  See
+// LogSet.RecoverAndContinue.
+func RecoverAndContinue() { def.logPanic(recover(), false) }
+
+// A Record is a single message to be logged.
+type Record struct {
+	Time    time.Time /* When the message was logged */
+	Level   Level     /* LevelNone for panics, which are always logged */
+	Message string    /* The formatted message */
+	Tags    []string  /* Tags, from DebugTagged */
+	Drop    bool      /* Set by a processor to not log the message */
+}
+
 // LogSet is a self-contained set of logging functions and variables.  It can
 // be used to turn on and off logging for various parts of large programs.
 type LogSet struct {
-	verboseOn *bool       /* Enables verbose logging */
-	debugOn   *bool       /* Enables debug logging */
-	logger    *log.Logger /* Alternate logger (such as syslog). */
-	changed   bool        /* One of the Log* functions has been called */
-	m         *sync.Mutex /* Mutex held during writes */
+	state    atomic.Uint32                /* Switches; see verboseBit, etc. */
+	logger   atomic.Pointer[log.Logger]   /* Alternate logger (such as syslog). */
+	made     bool                         /* Made by New */
+	m        sync.Mutex                   /* Mutex held during writes */
+	buf      atomic.Pointer[bufWriter]    /* Output buffer, if buffered */
+	children []*LogSet                    /* From NewMulti */
+	opPrefix string                       /* From WithOperation */
+	remote   atomic.Pointer[remoteWriter] /* From SetRemote */
+	eventLog *eventLog                    /* From SetWindowsEventLog */
+	routes   map[string]io.Writer         /* From SetTagRoute, under m */
+	outputs  []formattedOutput            /* From AddFormattedOutput, under m */
+
+	cfg     atomic.Pointer[settings]    /* Settings; see settings */
+	cfgM    sync.Mutex                  /* Serializes changes to cfg */
+	seq     atomic.Uint64               /* Last sequence number */
+	quiet   atomic.Pointer[quietWindow] /* Quiet hours */
+	promote atomic.Int32                /* From SetDebugPromotion */
+
+	heartbeats []func()   /* Stop functions from StartHeartbeat */
+	hbM        sync.Mutex /* Guards heartbeats */
+
+	hooks atomic.Pointer[[]levelHook] /* Hooks from AddLevelHook */
+	hookM sync.Mutex                  /* Serializes changes to hooks */
+
+	procs atomic.Pointer[[]func(*Record)] /* From AddProcessor */
+	procM sync.Mutex                      /* Serializes changes to procs */
+
+	subs atomic.Pointer[[]*subscriber] /* From SubscribeBlocking */
+	subM sync.Mutex                    /* Serializes changes to subs */
+
+	hist atomic.Pointer[history] /* From EnableTimeWindowHistory */
+
+	lap  time.Time  /* Time of the last Lap */
+	lapM sync.Mutex /* Guards lap */
+
+	once  map[string]struct{} /* Call sites which have logged once */
+	onceM sync.Mutex          /* Guards once */
+
+	every  map[string]time.Time /* When each LogEvery key last logged */
+	everyM sync.Mutex           /* Guards every */
+
+	sm       sync.Mutex           /* Serializes changes to the switches */
+	onChange func(old, new Level) /* Called when the level changes */
+	levelVar *slog.LevelVar       /* From SetSlogLevelVar */
 
 }
 
+// Switches in a LogSet's state.  The state is read without locking when
+// logging, and replaced as a whole when it's changed, so logging from many
+// goroutines doesn't contend on a lock.
+const (
+	verboseBit uint32 = 1 << iota /* Enables verbose logging */
+	debugBit                      /* Enables debug logging */
+	changedBit                    /* One of the Log* functions has been called */
+)
+
 // New returns a pointer to a new LogSet.
 func New() *LogSet {
-	/* Storage for the Ons */
-	v := false
-	d := false
-	return &LogSet{
-		verboseOn: &v,
-		debugOn:   &d,
-		logger:    nil,
-		changed:   false,
-		m:         &sync.Mutex{},
+	l := &LogSet{made: true}
+	l.cfg.Store(&settings{start: time.Now()})
+	return l
+}
+
+// SetStrictInit turns strict mode on or off.  In strict mode, using a LogSet
+// which wasn't made with New (e.g. a zero-value LogSet{}) causes a panic.
+// Otherwise, such a LogSet behaves as if it were made with New, which is the
+// default.  Strict mode is meant to catch mistakes during development:
+//
+//    func init() {
+//            easylogger.SetStrictInit(true)
+//    }
+func SetStrictInit(strict bool) { strictInit.Store(strict) }
+
+/* check panics if strict mode is on and l wasn't made with New */
+func (l *LogSet) check() {
+	if !l.made && strictInit.Load() {
+		panic("easylogger: LogSet not created with New")
 	}
 }
 
-/* Emit a message if doit is true */
-func (l *LogSet) log(doit *bool, format string, args ...interface{}) {
+// log emits a message if messages at the given level are logged.
+// LevelDebugOnly is treated as LevelDebug, and LevelNone messages are always
+// logged.  It returns true if the message was written.
+func (l *LogSet) log(level Level, format string, args ...interface{}) bool {
+	return l.logTagged(level, nil, format, args...)
+}
+
+/* logTagged is like log, but the message is tagged with tags */
+func (l *LogSet) logTagged(level Level, tags []string, format string,
+	args ...interface{}) bool {
+	/* Multi LogSets let their children decide */
+	if 0 != len(l.children) {
+		return l.forward(level, tags, format, args...)
+	}
 	/* Do it only if we're supposed to do it */
-	if nil == doit || !*doit {
-		return
+	level, ok := l.gate(level)
+	if !ok {
+		return false
+	}
+	return l.output(severity(level), tags, format, args...)
+}
+
+/* logs returns true if messages at the given level are logged */
+func (l *LogSet) logs(level Level) bool {
+	v, d, c := l.switches()
+	switch level {
+	case LevelVerbose:
+		/* If the state hasn't been changed (i.e. set by the flags),
+		verbose if debug is set */
+		return (v || (!c && d)) && !l.inQuietWindow()
+	case LevelDebug, LevelDebugOnly:
+		return d && !l.inQuietWindow()
+	default:
+		return true
+	}
+}
+
+// output emits a message regardless of which logging is turned on.  level is
+// the message's level, which is LevelNone for messages logged regardless of
+// level.  tags are the message's tags, from DebugTagged, if any.  output
+// returns false if a processor dropped the message.
+func (l *LogSet) output(level Level, tags []string, format string,
+	args ...interface{}) bool {
+	if 0 != len(l.children) {
+		return l.forward(level, tags, format, args...)
+	}
+	r := l.record(level, tags, format, args...)
+	if r.Drop {
+		return false
+	}
+	l.m.Lock()
+	l.write(r)
+	l.m.Unlock()
+	l.notify(r)
+	return true
+}
+
+// notify tells hooks and subscribers about r, and adds it to the history,
+// once it's been written.
+func (l *LogSet) notify(r Record) {
+	l.runHooks(r)
+	l.publish(r)
+	if h := l.hist.Load(); nil != h {
+		h.add(r)
+	}
+}
+
+// record formats a message and returns it as a Record, ready to be written
+// unless a processor dropped it.
+func (l *LogSet) record(level Level, tags []string, format string,
+	args ...interface{}) Record {
+	s := l.conf()
+	msg := fmt.Sprintf(format, args...)
+	if s.errStack {
+		msg += errorStacks(args)
+	}
+	if nil != s.transform {
+		msg = s.transform(msg)
+	}
+	if s.escapeNL {
+		msg = newlineEscaper.Replace(strings.TrimSuffix(msg, "\n"))
+	}
+	if s.uptimeOn {
+		msg = fmt.Sprintf("uptime=%v %s", s.uptime(), msg)
+	}
+	if 0 != len(tags) {
+		msg = "tags=" + strings.Join(tags, ",") + " " + msg
+	}
+	msg = s.hostField + s.pidField + msg
+	if s.seqOn {
+		msg = fmt.Sprintf("seq=%d %s", l.seq.Add(1), msg)
+	}
+	if nil != s.prefixFn {
+		msg = s.prefixFn() + msg
+	}
+	if 0 < s.maxLen && len(msg) > s.maxLen {
+		msg = truncate(msg, s.maxLen)
+	}
+	return l.process(Record{
+		Time:    time.Now(),
+		Level:   level,
+		Message: msg,
+		Tags:    tags,
+	})
+}
+
+/* write writes r to the logger in use */
+func (l *LogSet) write(r Record) {
+	s := l.conf()
+	t := l.target()
+	unlock := s.lockWriter(t.Writer(), nil != s.format || s.journald)
+	switch {
+	case nil != l.eventLog:
+		l.eventLog.report(r.Level, s.prefixOf(t)+r.Message)
+	case nil != s.format:
+		t.Writer().Write(s.format(r))
+	case s.journald:
+		/* Priority has to come first, and journald has the time */
+		io.WriteString(
+			t.Writer(),
+			journaldPriority(r.Level)+s.prefixOf(t)+r.Message+s.lineTerm(),
+		)
+	default:
+		s.printText(t, r, s.lockW)
+	}
+	unlock()
+	l.route(s, r)
+	l.writeOutputs(s, r)
+}
+
+// printText prints r's message with t, or with a copy of t which wraps
+// lines, uses the line terminator, puts the time in the time zone, trims
+// the caller's file name, adds the caller, or uses another prefix, if
+// SetWrap, SetLineTerminator, SetTimezone, SetCallerTrim,
+// RegisterVerbosityFlag, or Apply call for it.
+//
+// If a copy is used, it writes straight to t's writer without t's lock, so
+// the writer's lock from SetAtomicWrites is taken instead, unless locked is
+// true because it's already held.
+func (s *settings) printText(t *log.Logger, r Record, locked bool) {
+	var (
+		w       = t.Writer()
+		prefix  = t.Prefix()
+		flags   = t.Flags()
+		msg     = r.Message
+		changed bool
+	)
+	if nil != s.prefix {
+		prefix, changed = *s.prefix, true
 	}
+	if nil != s.term {
+		w, changed = termWriter{w: w, term: *s.term}, true
+	}
+	if s.shortfile && 0 == flags&(log.Lshortfile|log.Llongfile) {
+		flags, changed = flags|log.Lshortfile, true
+	}
+	/* Wrap before terminating, but only if writing to a terminal */
+	if s.wrap {
+		if width := termWidth(t.Writer()); len(wrapIndent) < width {
+			w, changed = wrapWriter{w: w, width: width}, true
+		}
+	}
+	/* We put the time where the logger would */
+	if nil != s.tz && 0 != flags&timeFlags {
+		ts := timestamp(r.Time.In(s.tz), flags)
+		if 0 != flags&log.Lmsgprefix {
+			prefix, msg = ts, prefix+msg
+		} else {
+			prefix += ts
+		}
+		flags &^= timeFlags | log.LUTC | log.Lmsgprefix
+		changed = true
+	}
+	/* Likewise the caller */
+	if "" != s.trim && log.Llongfile == flags&(log.Lshortfile|log.Llongfile) {
+		if _, file, line, ok := runtime.Caller(callDepth() - 1); ok {
+			caller := fmt.Sprintf(
+				"%s:%d: ",
+				strings.TrimPrefix(file, s.trim),
+				line,
+			)
+			if 0 != flags&log.Lmsgprefix {
+				prefix, msg = "", caller+prefix+msg
+			} else {
+				msg = caller + msg
+			}
+			flags &^= log.Llongfile | log.Lmsgprefix
+			changed = true
+		}
+	}
+	if changed {
+		if !locked {
+			m := writeLock(t.Writer())
+			m.Lock()
+			defer m.Unlock()
+		}
+		t = log.New(w, prefix, flags)
+	}
+	printCaller(t, msg)
+}
+
+/* target returns the logger to which messages are sent */
+func (l *LogSet) target() *log.Logger {
 	/* Work out which logger to use */
-	if l.logger != nil { /* User-assigned logger */
-		l.logger.Printf(format, args...)
-	} else { /* Default logger */
-		log.Printf(format, args...)
+	if lg := l.logger.Load(); nil != lg { /* User-assigned logger */
+		return lg
 	}
+	return log.Default() /* Default logger */
 }
 
 /* Verbose logs a message if verbose messages are turned on */
 func (l *LogSet) Verbose(format string, args ...interface{}) {
-	doit := *l.verboseOn
-	/* If the state hasn't been changed (i.e. set by the flags), verbose
-	if debug is set */
-	if !l.changed && !*l.verboseOn && *l.debugOn {
-		doit = true
-	}
-	l.log(&doit, format, args...)
+	l.check()
+	l.log(LevelVerbose, format, args...)
 }
 
 /* Debug logs a message if debugging messages are turned on */
 func (l *LogSet) Debug(format string, args ...interface{}) {
-	l.log(l.debugOn, format, args...)
+	l.check()
+	l.log(LevelDebug, format, args...)
+}
+
+// Log logs a message at a level chosen at runtime, if messages at that
+// level are logged.  Log(LevelVerbose, ...) is the same as Verbose, and
+// Log(LevelDebug, ...) and Log(LevelDebugOnly, ...) are the same as Debug.
+// LevelNone messages are always logged, and are treated like panics by
+// AddLevelHook, SetJournald, and the like.
+//
+//    level := easylogger.LevelDebug
+//    if maxRetries < retries {
+//            level = easylogger.LevelVerbose
+//    }
+//    ls.Log(level, "Retry %d failed: %v", retries, err)
+func (l *LogSet) Log(level Level, format string, args ...interface{}) {
+	l.check()
+	l.log(level, format, args...)
+}
+
+// VerboseIf logs a message if cond is true and verbose messages are turned
+// on.  The message isn't formatted if cond is false.
+//
+//    ls.VerboseIf(3 < retries, "Many retries: %d", retries)
+func (l *LogSet) VerboseIf(cond bool, format string, args ...interface{}) {
+	if cond {
+		l.Verbose(format, args...)
+	}
+}
+
+// DebugIf logs a message if cond is true and debugging messages are turned
+// on.  The message isn't formatted if cond is false.
+func (l *LogSet) DebugIf(cond bool, format string, args ...interface{}) {
+	if cond {
+		l.Debug(format, args...)
+	}
+}
+
+// RecoverAndLog recovers from a panic, logs the panic value and a stack
+// trace, and then panics again with the same value, preserving the usual
+// crash behavior.  It must be deferred directly:
+//
+//    func main() {
+//            defer ls.RecoverAndLog()
+//            /* ... */
+//    }
+//
+// The panic is logged whether or not verbose or debug logging is on.
+func (l *LogSet) RecoverAndLog() { l.logPanic(recover(), true) }
+
+// RecoverAndContinue is like RecoverAndLog, but the panic is swallowed
+// instead of being re-raised.  This is meant for goroutines which should
+// survive a panic:
+//
+//    go func() {
+//            defer ls.RecoverAndContinue()
+//            /* ... */
+//    }()
+func (l *LogSet) RecoverAndContinue() { l.logPanic(recover(), false) }
+
+/* logPanic logs r, if it's not nil, and panics again if repanic is true */
+func (l *LogSet) logPanic(r interface{}, repanic bool) {
+	/* No panic, nothing to do */
+	if nil == r {
+		return
+	}
+	l.output(LevelNone, nil, "Panic: %v\n%s", r, debug.Stack())
+	l.Flush()
+	if repanic {
+		panic(r)
+	}
 }
 
 /* logSwitch switches on/off verbose and debug logging */
-func (l *LogSet) logSwitch(v, d bool) {
-	/* Make sure we have bools allocated */
-	if nil == l.verboseOn {
-		b := false
-		l.verboseOn = &b
+func (l *LogSet) logSwitch(v, d bool) { l.setSwitches(v, d, true) }
+
+// setSwitches sets the verbose and debug switches, and whether they've been
+// changed by one of the Log* functions.
+func (l *LogSet) setSwitches(v, d, changed bool) {
+	l.check()
+	l.sm.Lock()
+	defer l.sm.Unlock()
+	old := levelOf(l.switches())
+	/* Switch the switches, and note whether there's been a change */
+	var s uint32
+	if v {
+		s |= verboseBit
+	}
+	if d {
+		s |= debugBit
+	}
+	if changed {
+		s |= changedBit
+	}
+	l.state.Store(s)
+	l.syncLevelVar()
+	/* Tell someone about it */
+	if cur := levelOf(v, d, changed); nil != l.onChange && old != cur {
+		l.onChange(old, cur)
+	}
+}
+
+// setSwitch turns a single switch on or off, calling the SetOnLevelChange
+// function if that changes the level.  It's used by command-line flags.
+func (l *LogSet) setSwitch(bit uint32, on bool) {
+	l.sm.Lock()
+	defer l.sm.Unlock()
+	old := levelOf(l.switches())
+	s := l.state.Load()
+	if on {
+		s |= bit
+	} else {
+		s &^= bit
 	}
-	if nil == l.debugOn {
-		b := false
-		l.debugOn = &b
+	l.state.Store(s)
+	l.syncLevelVar()
+	if cur := levelOf(l.switches()); nil != l.onChange && old != cur {
+		l.onChange(old, cur)
 	}
-	/* Switch the switches */
-	*l.verboseOn = v
-	*l.debugOn = d
-	/* Note there's been a change */
-	l.changed = true
+}
+
+// switches returns the verbose and debug switches and whether they've been
+// changed by one of the Log* functions.
+func (l *LogSet) switches() (verbose, debug, changed bool) {
+	s := l.state.Load()
+	return 0 != s&verboseBit, 0 != s&debugBit, 0 != s&changedBit
 }
 
 // LogVerbose turns on Verbose logging
@@ -255,8 +712,169 @@ func (l *LogSet) LogDebugOnly() { l.logSwitch(false, true) }
 
 // SetLogger causes logger to be used for log output.  This may be nil to use
 // the default logger.
+//
+// Messages are written with logger, and so with logger's lock held, unless
+// a setting such as SetFormatFunc, SetJournald, SetUTC, SetTimezone,
+// SetLineTerminator, or SetWrap changes how they look.  Then they're written
+// straight to logger's writer, with a lock shared by all LogSets (see
+// SetAtomicWrites) but not logger's own.  Such messages aren't kept apart
+// from messages logged with logger directly, e.g. with logger.Printf, so in
+// that case logger's writer should be safe for concurrent use, as an
+// *os.File is.
 func (l *LogSet) SetLogger(logger *log.Logger) {
-	l.logger = logger
+	l.check()
+	l.logger.Store(logger)
+}
+
+// SetUTC causes timestamps to be in UTC if utc is true, or in local time (the
+// default) if utc is false.  Only l's messages are affected; the logger's
+// flags are left alone, so other users of the logger, which is the standard
+// logger unless SetLogger has been called, aren't.  It undoes SetTimezone.
+func (l *LogSet) SetUTC(utc bool) {
+	var tz *time.Location
+	if utc {
+		tz = time.UTC
+	}
+	l.set(func(s *settings) { s.tz = tz })
+}
+
+// SetMessageTransform causes f to be called on every message after it's
+// been formatted, and what f returns to be logged instead.  This is useful
+// for sanitizing messages, e.g. by removing ANSI escape codes or control
+// characters.  As f is called for every message logged, a slow f will slow
+// down logging.  f may be nil to log messages unchanged, which is the
+// default.
+func (l *LogSet) SetMessageTransform(f func(string) string) {
+	l.set(func(s *settings) { s.transform = f })
+}
+
+// SetEscapeNewlines causes newlines and carriage returns in messages to be
+// replaced with \n and \r if escape is true, so that every message is logged
+// on exactly one line.  This prevents untrusted input in a message from
+// forging log entries.  A single trailing newline is removed rather than
+// escaped.  Escaping happens after any transform set with
+// SetMessageTransform, and applies to all messages, including the stack
+// traces logged by RecoverAndLog.  Newlines aren't escaped by default.
+func (l *LogSet) SetEscapeNewlines(escape bool) {
+	l.set(func(s *settings) { s.escapeNL = escape })
+}
+
+// SetSequence causes each message to be prefixed with seq=N, where N is one
+// more than the previous message's N, if on is true.  Numbering starts at 1
+// and carries on if SetSequence is turned off and on again.  Sequence numbers
+// make it easy to spot messages lost or reordered on their way to wherever
+// logs end up.  Each LogSet has its own sequence.  Messages aren't numbered
+// by default.
+func (l *LogSet) SetSequence(on bool) {
+	l.set(func(s *settings) { s.seqOn = on })
+}
+
+// SetFormatFunc causes f to be used to render every message, for formats
+// the log package can't produce, such as CSV.  The bytes f returns are
+// written as-is to the logger's output, so should include a trailing newline
+// if one is wanted.  The logger's prefix and flags aren't used, and
+// SetJournald has no effect.  Which messages are logged is unchanged.  f may
+// be nil to go back to logging through the logger, which is the default.
+//
+//    ls.SetFormatFunc(func(r easylogger.Record) []byte {
+//            return []byte(fmt.Sprintf("%d,%s,%q\n",
+//                    r.Time.Unix(), r.Level, r.Message))
+//    })
+func (l *LogSet) SetFormatFunc(f func(Record) []byte) {
+	l.set(func(s *settings) { s.format = f })
+}
+
+// SetHostField causes each message to be prefixed with host=hostname if on
+// is true, for telling apart messages from many hosts once they've been
+// collected in one place.  The hostname is looked up with os.Hostname when
+// SetHostField is called, not for every message, and is "unknown" if the
+// lookup fails.  The hostname comes after any sequence number and before any
+// process ID.
+func (l *LogSet) SetHostField(on bool) {
+	var field string
+	if on {
+		h, err := os.Hostname()
+		if nil != err {
+			h = "unknown"
+		}
+		field = "host=" + h + " "
+	}
+	l.set(func(s *settings) { s.hostField = field })
+}
+
+// SetUptimeField causes each message to be prefixed with uptime=d if on is
+// true, where d is the time since l was made with New, to the millisecond.
+// For a LogSet not made with New, d is the time since the program started.
+// The default LogSet is made when the program starts.  The uptime isn't
+// affected by changes to the system clock, which makes it useful for working
+// out how far apart messages were when the clock's untrustworthy.  The
+// uptime comes after any process ID.
+func (l *LogSet) SetUptimeField(on bool) {
+	l.set(func(s *settings) { s.uptimeOn = on })
+}
+
+/* uptime returns the time since l was made, or the program started */
+func (s *settings) uptime() time.Duration {
+	start := s.start
+	if start.IsZero() {
+		start = processStart
+	}
+	return time.Since(start).Round(time.Millisecond)
+}
+
+// SetPIDField causes each message to be prefixed with pid=pid if on is true,
+// where pid is the process ID.
+func (l *LogSet) SetPIDField(on bool) {
+	var field string
+	if on {
+		field = fmt.Sprintf("pid=%d ", os.Getpid())
+	}
+	l.set(func(s *settings) { s.pidField = field })
+}
+
+// SetPrefixFunc causes f to be called for every message logged, and what it
+// returns to be put at the start of the message, for prefixes which change,
+// such as the current tenant:
+//
+//    ls.SetPrefixFunc(func() string {
+//            return "[" + currentTenant() + "] "
+//    })
+//
+// f is only called for messages which are logged.  Its prefix comes after
+// the logger's prefix and timestamp, and before any sequence number,
+// hostname, or the like.  f may be nil to stop calling the previous f.
+func (l *LogSet) SetPrefixFunc(f func() string) {
+	l.set(func(s *settings) { s.prefixFn = f })
+}
+
+// SetMaxMessageLen causes messages longer than n bytes to be cut short to at
+// most n bytes, ending with " [truncated]", for log collectors which reject
+// long lines.  The marker counts towards the n bytes; if n is too small to
+// hold it, messages are just cut to n bytes without it.  Messages are only
+// cut between UTF-8 characters.  The limit applies to the message including
+// any sequence number, hostname, and process ID, but not the logger's
+// timestamp and prefix.  Messages are cut before being passed to a
+// SetFormatFunc function.  An n of 0 or less, the default, means there is no
+// limit.
+func (l *LogSet) SetMaxMessageLen(n int) {
+	l.set(func(s *settings) { s.maxLen = n })
+}
+
+// truncatedMarker is put at the end of messages cut by truncate.
+const truncatedMarker = " [truncated]"
+
+// truncate cuts s, which is longer than n bytes, to at most n bytes, without
+// splitting a UTF-8 character, and notes that it's been cut if there's room.
+func truncate(s string, n int) string {
+	marker := truncatedMarker
+	if n < len(marker) {
+		marker = ""
+	}
+	n -= len(marker)
+	for 0 < n && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + marker
 }
 
 // Pause pauses logging.  Calls to Verbose and Debug will block until Resume
@@ -264,6 +882,7 @@ func (l *LogSet) SetLogger(logger *log.Logger) {
 // for logfile rotation without risk of losing data.  See Resume for an
 // example.
 func (l *LogSet) Pause() {
+	l.check()
 	l.m.Lock()
 }
 
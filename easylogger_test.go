@@ -0,0 +1,81 @@
+package easylogger
+
+/*
+ * easylogger_test.go
+ * Tests for the basics
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+// bufferSet returns a LogSet which logs everything to the returned buffer,
+// with no prefix or flags.
+func bufferSet() (*LogSet, *bytes.Buffer) {
+	var b bytes.Buffer
+	l := New()
+	l.SetLogger(log.New(&b, "", 0))
+	l.LogDebug()
+	return l, &b
+}
+
+func TestEscapeNewlines(t *testing.T) {
+	for _, c := range []struct {
+		msg  string
+		want string
+	}{
+		{"plain", "plain\n"},
+		{"trailing\n", "trailing\n"},
+		{
+			"user=bob\n2012/01/01 00:00:00 user=admin logged in",
+			`user=bob\n2012/01/01 00:00:00 user=admin logged in` + "\n",
+		},
+		{
+			"user=bob\r\nuser=admin logged in",
+			`user=bob\r\nuser=admin logged in` + "\n",
+		},
+		{"carriage\roverwrite", `carriage\roverwrite` + "\n"},
+		{"\r\n\r\n", `\r\n\r` + "\n"},
+		{"\n\n", `\n` + "\n"},
+	} {
+		l, b := bufferSet()
+		l.SetEscapeNewlines(true)
+		l.Debug("%s", c.msg)
+		if got := b.String(); got != c.want {
+			t.Errorf("Message %q: got %q, want %q", c.msg, got, c.want)
+		}
+		if n := strings.Count(b.String(), "\n"); 1 != n {
+			t.Errorf("Message %q: got %d lines, want 1", c.msg, n)
+		}
+	}
+}
+
+func TestEscapeNewlinesOff(t *testing.T) {
+	l, b := bufferSet()
+	l.Debug("one\r\ntwo")
+	if got, want := b.String(), "one\r\ntwo\n"; got != want {
+		t.Errorf("Got %q, want %q", got, want)
+	}
+}
+
+func TestCaptureNoPrefix(t *testing.T) {
+	l, b := bufferSet()
+	if err := l.Apply(Config{Prefix: "app: ", Sequence: true}); nil != err {
+		t.Fatalf("Apply: %v", err)
+	}
+	got := l.Capture(func() { l.Debug("hello") })
+	if 1 != len(got) || "seq=1 hello" != got[0] {
+		t.Errorf("Captured %q, want [\"seq=1 hello\"]", got)
+	}
+	/* The prefix should be back afterwards */
+	l.Debug("after")
+	if got, want := b.String(), "app: seq=2 after\n"; got != want {
+		t.Errorf("After Capture: got %q, want %q", got, want)
+	}
+}
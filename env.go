@@ -0,0 +1,94 @@
+package easylogger
+
+/*
+ * env.go
+ * Configuration from environment variables
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ConfigureFromEnv configures the default LogSet from environment
+// variables.  See LogSet.ConfigureFromEnv.
+func ConfigureFromEnv(prefix string) error {
+	return def.ConfigureFromEnv(prefix)
+}
+
+// MustConfigure returns a new LogSet configured from environment variables
+// starting with prefix, as with ConfigureFromEnv, and panics if the
+// configuration is invalid, with an error naming prefix and wrapping
+// ConfigureFromEnv's.  It's meant for package-level variables, where
+// handling an error is awkward:
+//
+//    var ls = easylogger.MustConfigure("APP_")
+func MustConfigure(prefix string) *LogSet {
+	l := New()
+	if err := l.ConfigureFromEnv(prefix); nil != err {
+		panic(fmt.Errorf("easylogger: MustConfigure(%q): %w", prefix, err))
+	}
+	return l
+}
+
+// ConfigureFromEnv reads a Config from environment variables named prefix
+// followed by the upper-cased JSON keys of Config's fields, and applies it
+// with Apply.  For example, with a prefix of APP_:
+//
+//    APP_LEVEL=debug APP_PREFIX="app: " APP_MAX_MESSAGE_LEN=1024 ./app
+//
+// Booleans are parsed by strconv.ParseBool, and unset or empty variables
+// leave their settings alone.  An error is returned, and nothing's changed,
+// if a variable can't be parsed or the level isn't valid.  If
+// SetStrictConfig has turned strict parsing on, variables starting with
+// prefix which aren't for a field of Config are an error as well, unless
+// prefix is empty, as then every variable would start with it.
+func (l *LogSet) ConfigureFromEnv(prefix string) error {
+	var c Config
+	cv := reflect.ValueOf(&c).Elem()
+	known := make(map[string]bool)
+	for i := 0; i < cv.NumField(); i++ {
+		key, _, _ := strings.Cut(cv.Type().Field(i).Tag.Get("json"), ",")
+		name := prefix + strings.ToUpper(key)
+		known[name] = true
+		val := os.Getenv(name)
+		if "" == val {
+			continue
+		}
+		switch f := cv.Field(i); f.Kind() {
+		case reflect.String:
+			f.SetString(val)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(val)
+			if nil != err {
+				return fmt.Errorf("easylogger: parsing %s: %w", name, err)
+			}
+			f.SetBool(b)
+		case reflect.Int:
+			n, err := strconv.Atoi(val)
+			if nil != err {
+				return fmt.Errorf("easylogger: parsing %s: %w", name, err)
+			}
+			f.SetInt(int64(n))
+		}
+	}
+	if strictConfig.Load() && "" != prefix {
+		for _, kv := range os.Environ() {
+			name, _, _ := strings.Cut(kv, "=")
+			if strings.HasPrefix(name, prefix) && !known[name] {
+				return fmt.Errorf("easylogger: unknown variable %s", name)
+			}
+		}
+	}
+	/* Only the level can be bad */
+	if err := l.Apply(c); nil != err {
+		return fmt.Errorf("%w, from %sLEVEL", err, prefix)
+	}
+	return nil
+}
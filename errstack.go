@@ -0,0 +1,68 @@
+package easylogger
+
+/*
+ * errstack.go
+ * Log the stacks of errors which have them
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SetExtractErrorStack causes the default LogSet to log the stacks of
+// errors which have them.  See LogSet.SetExtractErrorStack.
+func SetExtractErrorStack(on bool) { def.SetExtractErrorStack(on) }
+
+// SetExtractErrorStack causes the stack traces of errors passed as
+// arguments to be logged after the message if on is true, for errors from
+// packages such as github.com/pkg/errors which record where they were
+// made.  An error has a stack trace if it, or an error it wraps, has a
+// StackTrace method which takes no arguments and returns one value.  If
+// more than one error in the chain has one, the innermost's is used, as
+// it's closest to where the error happened.
+//
+// Each stack is put after the message as stack=, followed by the stack as
+// formatted by %+v, which for github.com/pkg/errors is one frame per line.
+// SetEscapeNewlines keeps it all on one line.  Errors without stack traces
+// are logged as usual.
+func (l *LogSet) SetExtractErrorStack(on bool) {
+	l.set(func(s *settings) { s.errStack = on })
+}
+
+/* errorStacks returns the stacks of the errors in args, as fields */
+func errorStacks(args []interface{}) string {
+	var b strings.Builder
+	for _, arg := range args {
+		err, ok := arg.(error)
+		if !ok {
+			continue
+		}
+		if st, ok := stackTrace(err); ok {
+			fmt.Fprintf(&b, " stack=%+v", st)
+		}
+	}
+	return b.String()
+}
+
+// stackTrace returns the value returned by the StackTrace method of the
+// innermost error in err's chain which has one.
+func stackTrace(err error) (interface{}, bool) {
+	var (
+		st    interface{}
+		found bool
+	)
+	for ; nil != err; err = errors.Unwrap(err) {
+		m := reflect.ValueOf(err).MethodByName("StackTrace")
+		if !m.IsValid() || 0 != m.Type().NumIn() || 1 != m.Type().NumOut() {
+			continue
+		}
+		st, found = m.Call(nil)[0].Interface(), true
+	}
+	return st, found
+}
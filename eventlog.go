@@ -0,0 +1,60 @@
+package easylogger
+
+/*
+ * eventlog.go
+ * Send messages to the Windows Event Log
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+// An eventLog is an event source to which messages are reported.
+type eventLog struct {
+	report func(level Level, msg string) error
+	close  func() error
+}
+
+// SetWindowsEventLog causes messages from the default LogSet to be sent to
+// the Windows Event Log.  See LogSet.SetWindowsEventLog.
+func SetWindowsEventLog(source string) error {
+	return def.SetWindowsEventLog(source)
+}
+
+// SetWindowsEventLog causes messages to be sent to the Windows Event Log,
+// from the event source named source, instead of to the logger.  This is
+// meant for programs run as Windows services, much as SetLogger with a
+// log/syslog logger is for Unix daemons.  Panics logged by RecoverAndLog are
+// reported as errors and other messages as information, as easylogger has
+// no warnings.  The logger's prefix is kept, but not its timestamp or other
+// flags, as the Event Log has its own.
+//
+// If the source isn't already registered, SetWindowsEventLog tries to
+// register it, which needs administrator rights.  If it can't, messages are
+// still logged, but the Event Viewer may not display them neatly.  An error
+// is only returned if the source can't be opened at all, or on platforms
+// other than Windows.  An empty source stops sending messages to the Event
+// Log.  Close also stops sending messages to the Event Log.
+func (l *LogSet) SetWindowsEventLog(source string) error {
+	var (
+		el  *eventLog
+		err error
+	)
+	if "" != source {
+		if el, err = openEventLog(source); nil != err {
+			return err
+		}
+	}
+	l.m.Lock()
+	old := l.eventLog
+	l.eventLog = el
+	l.m.Unlock()
+	if nil != old {
+		return old.close()
+	}
+	return nil
+}
+
+/* closeEventLog stops sending messages to the Event Log */
+func (l *LogSet) closeEventLog() error {
+	return l.SetWindowsEventLog("")
+}
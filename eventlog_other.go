@@ -0,0 +1,24 @@
+//go:build !windows
+
+package easylogger
+
+/*
+ * eventlog_other.go
+ * The Windows Event Log is not available
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"fmt"
+	"runtime"
+)
+
+/* openEventLog returns an error, as there's no Event Log */
+func openEventLog(source string) (*eventLog, error) {
+	return nil, fmt.Errorf(
+		"easylogger: the Windows Event Log is not available on %s",
+		runtime.GOOS,
+	)
+}
@@ -0,0 +1,137 @@
+package easylogger
+
+/*
+ * eventlog_windows.go
+ * Windows Event Log API
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	advapi32                  = syscall.NewLazyDLL("advapi32.dll")
+	procRegisterEventSourceW  = advapi32.NewProc("RegisterEventSourceW")
+	procDeregisterEventSource = advapi32.NewProc("DeregisterEventSource")
+	procReportEventW          = advapi32.NewProc("ReportEventW")
+	procRegCreateKeyExW       = advapi32.NewProc("RegCreateKeyExW")
+	procRegSetValueExW        = advapi32.NewProc("RegSetValueExW")
+)
+
+/* Event types, from winnt.h */
+const (
+	eventlogErrorType       = 0x0001
+	eventlogInformationType = 0x0004
+)
+
+// eventSourceKey is the registry key under which event sources are
+// registered.
+const eventSourceKey = `SYSTEM\CurrentControlSet\Services\EventLog\Application\`
+
+// eventMessageFile has a message for each event ID from 1 to 1000 which is
+// just the message itself, which saves shipping a message file.
+const eventMessageFile = `%SystemRoot%\System32\EventCreate.exe`
+
+/* openEventLog registers source, if it can, and opens it */
+func openEventLog(source string) (*eventLog, error) {
+	name, err := syscall.UTF16PtrFromString(source)
+	if nil != err {
+		return nil, fmt.Errorf("easylogger: bad event source %q: %w", source, err)
+	}
+	registerEventSource(source) /* Best effort */
+	h, _, err := procRegisterEventSourceW.Call(0, uintptr(unsafe.Pointer(name)))
+	if 0 == h {
+		return nil, fmt.Errorf("easylogger: opening event source %q: %w", source, err)
+	}
+	return &eventLog{
+		report: func(level Level, msg string) error {
+			return reportEvent(h, level, msg)
+		},
+		close: func() error {
+			if r, _, err := procDeregisterEventSource.Call(h); 0 == r {
+				return fmt.Errorf("easylogger: closing event source %q: %w", source, err)
+			}
+			return nil
+		},
+	}, nil
+}
+
+/* reportEvent reports msg to the event source h */
+func reportEvent(h uintptr, level Level, msg string) error {
+	etype := eventlogInformationType
+	if LevelNone == level {
+		etype = eventlogErrorType
+	}
+	s, err := syscall.UTF16PtrFromString(msg)
+	if nil != err {
+		return err
+	}
+	ss := []*uint16{s}
+	if r, _, err := procReportEventW.Call(
+		h,
+		uintptr(etype),
+		0,                               /* Category */
+		1,                               /* Event ID */
+		0,                               /* User SID */
+		uintptr(len(ss)),                /* Number of strings */
+		0,                               /* Raw data size */
+		uintptr(unsafe.Pointer(&ss[0])), /* Strings */
+		0,                               /* Raw data */
+	); 0 == r {
+		return err
+	}
+	return nil
+}
+
+// registerEventSource tries to add source to the registry so that the Event
+// Viewer can display its messages.  Errors, usually from not running as an
+// administrator, are ignored.
+func registerEventSource(source string) {
+	path, err := syscall.UTF16PtrFromString(eventSourceKey + source)
+	if nil != err {
+		return
+	}
+	var k syscall.Handle
+	if r, _, _ := procRegCreateKeyExW.Call(
+		uintptr(syscall.HKEY_LOCAL_MACHINE),
+		uintptr(unsafe.Pointer(path)),
+		0, /* Reserved */
+		0, /* Class */
+		0, /* Options */
+		uintptr(syscall.KEY_WRITE),
+		0, /* Security attributes */
+		uintptr(unsafe.Pointer(&k)),
+		0, /* Disposition */
+	); 0 != r {
+		return
+	}
+	defer syscall.RegCloseKey(k)
+	file, _ := syscall.UTF16FromString(eventMessageFile)
+	setRegValue(k, "EventMessageFile", syscall.REG_EXPAND_SZ,
+		unsafe.Pointer(&file[0]), 2*len(file))
+	types := uint32(7) /* Error, warning, and information */
+	setRegValue(k, "TypesSupported", syscall.REG_DWORD,
+		unsafe.Pointer(&types), 4)
+}
+
+/* setRegValue sets the value named name of key k */
+func setRegValue(k syscall.Handle, name string, typ uint32,
+	data unsafe.Pointer, n int) {
+	p, err := syscall.UTF16PtrFromString(name)
+	if nil != err {
+		return
+	}
+	procRegSetValueExW.Call(
+		uintptr(k),
+		uintptr(unsafe.Pointer(p)),
+		0, /* Reserved */
+		uintptr(typ),
+		uintptr(data),
+		uintptr(n),
+	)
+}
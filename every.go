@@ -0,0 +1,52 @@
+package easylogger
+
+/*
+ * every.go
+ * Log at most once per interval
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import "time"
+
+// LogEvery logs a message from the default LogSet at most once per interval
+// for each key.  See LogSet.LogEvery.
+func LogEvery(key string, interval time.Duration, level Level,
+	format string, args ...interface{}) {
+	def.LogEvery(key, interval, level, format, args...)
+}
+
+// LogEvery logs a message at the given level, as with Log, unless a message
+// with the same key was logged less than interval ago.  This is handy for
+// status messages in busy loops:
+//
+//    for _, job := range jobs {
+//            ls.LogEvery("progress", time.Minute, easylogger.LevelVerbose,
+//                    "Done %d/%d jobs", done, len(jobs))
+//            /* ... */
+//    }
+//
+// Only messages which are logged start a new interval, and the message isn't
+// formatted if it's not logged.  The time each key last logged is kept until
+// Reset, so keys should come from a small, fixed set.  LogEvery is safe for
+// concurrent use.
+func (l *LogSet) LogEvery(key string, interval time.Duration, level Level,
+	format string, args ...interface{}) {
+	l.check()
+	if !l.Enabled(level) {
+		return
+	}
+	now := time.Now()
+	l.everyM.Lock()
+	if last, ok := l.every[key]; ok && now.Sub(last) < interval {
+		l.everyM.Unlock()
+		return
+	}
+	if nil == l.every {
+		l.every = make(map[string]time.Time)
+	}
+	l.every[key] = now
+	l.everyM.Unlock()
+	l.log(level, format, args...)
+}
@@ -0,0 +1,3 @@
+module github.com/kd5pbo/easylogger
+
+go 1.22
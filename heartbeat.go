@@ -0,0 +1,65 @@
+package easylogger
+
+/*
+ * heartbeat.go
+ * Periodic "still alive" messages
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"sync"
+	"time"
+)
+
+// StartHeartbeat starts logging msg from the default LogSet every interval.
+// See LogSet.StartHeartbeat.
+func StartHeartbeat(interval time.Duration, msg string) (stop func()) {
+	return def.StartHeartbeat(interval, msg)
+}
+
+// StartHeartbeat logs msg as a verbose message every interval, from another
+// goroutine, until the returned function or Close is called.  This shows
+// that the program, and its logging, is still alive.  The returned function
+// may be called more than once.  An interval of 0 or less starts no
+// heartbeat, and the returned function does nothing.
+func (l *LogSet) StartHeartbeat(interval time.Duration,
+	msg string) (stop func()) {
+	var (
+		done = make(chan struct{})
+		once sync.Once
+	)
+	stop = func() { once.Do(func() { close(done) }) }
+	/* time.NewTicker would panic, in a goroutine we can't recover */
+	if 0 >= interval {
+		return stop
+	}
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				l.Verbose("%s", msg)
+			case <-done:
+				return
+			}
+		}
+	}()
+	/* Close will stop it, too */
+	l.hbM.Lock()
+	defer l.hbM.Unlock()
+	l.heartbeats = append(l.heartbeats, stop)
+	return stop
+}
+
+/* stopHeartbeats stops all of the heartbeats started by StartHeartbeat */
+func (l *LogSet) stopHeartbeats() {
+	l.hbM.Lock()
+	defer l.hbM.Unlock()
+	for _, stop := range l.heartbeats {
+		stop()
+	}
+	l.heartbeats = nil
+}
@@ -0,0 +1,89 @@
+package easylogger
+
+/*
+ * history.go
+ * Keep recent records
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"sync"
+	"time"
+)
+
+// history holds the records logged in the last d.
+type history struct {
+	d    time.Duration
+	m    sync.Mutex
+	recs []Record /* Oldest first */
+}
+
+/* add adds r to the history */
+func (h *history) add(r Record) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.prune(r.Time)
+	h.recs = append(h.recs, r)
+}
+
+/* prune removes records older than h.d before now.  h.m must be held. */
+func (h *history) prune(now time.Time) {
+	cutoff := now.Add(-h.d)
+	n := 0
+	for n < len(h.recs) && h.recs[n].Time.Before(cutoff) {
+		n++
+	}
+	clear(h.recs[:n]) /* Let the messages be collected */
+	h.recs = h.recs[n:]
+}
+
+// EnableTimeWindowHistory causes the default LogSet to remember the
+// messages it logged in the last d.  See LogSet.EnableTimeWindowHistory.
+func EnableTimeWindowHistory(d time.Duration) { def.EnableTimeWindowHistory(d) }
+
+// HistorySince returns the messages the default LogSet logged since t.  See
+// LogSet.HistorySince.
+func HistorySince(t time.Time) []Record { return def.HistorySince(t) }
+
+// EnableTimeWindowHistory causes l to remember the messages it logs for d,
+// so they can be retrieved with HistorySince, for example to attach to an
+// error report:
+//
+//    ls.EnableTimeWindowHistory(time.Minute)
+//    /* ... */
+//    report.Logs = ls.HistorySince(time.Now().Add(-30 * time.Second))
+//
+// Only messages which are logged are remembered, after processors have had
+// their way with them.  Messages older than d are forgotten as new messages
+// are logged or HistorySince is called.  Calling EnableTimeWindowHistory
+// again forgets the messages remembered so far, and a d of 0 or less stops
+// remembering messages.
+func (l *LogSet) EnableTimeWindowHistory(d time.Duration) {
+	if 0 >= d {
+		l.hist.Store(nil)
+		return
+	}
+	l.hist.Store(&history{d: d})
+}
+
+// HistorySince returns, oldest first, the messages remembered because of
+// EnableTimeWindowHistory which were logged at or after t.  It returns nil
+// if EnableTimeWindowHistory hasn't been called.
+func (l *LogSet) HistorySince(t time.Time) []Record {
+	h := l.hist.Load()
+	if nil == h {
+		return nil
+	}
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.prune(time.Now())
+	var rs []Record
+	for _, r := range h.recs {
+		if !r.Time.Before(t) {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
@@ -0,0 +1,69 @@
+package easylogger
+
+/*
+ * hooks.go
+ * Functions called for logged messages
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+// levelHook is a function called for messages at least as severe as min.
+type levelHook struct {
+	min Level
+	fn  func(Record)
+}
+
+// AddLevelHook causes fn to be called for every message logged by the
+// default LogSet which is at least as severe as min.  See
+// LogSet.AddLevelHook.
+func AddLevelHook(min Level, fn func(Record)) { def.AddLevelHook(min, fn) }
+
+// AddLevelHook causes fn to be called with every message l logs which is at
+// least as severe as min, after it's been written.  From most to least
+// severe, messages are panics (LevelNone), verbose messages (LevelVerbose),
+// and debug messages (LevelDebug, or LevelDebugOnly).  For example, to send
+// panics to an alerting service:
+//
+//    ls.AddLevelHook(easylogger.LevelNone, func(r easylogger.Record) {
+//            alert(r.Message)
+//    })
+//
+// Any number of hooks may be added, each with its own min, and they are
+// called in the order they were added.  Messages which aren't logged don't
+// cause hooks to be called.  fn is called synchronously, so should be quick,
+// and must not log with l.
+func (l *LogSet) AddLevelHook(min Level, fn func(Record)) {
+	l.hookM.Lock()
+	defer l.hookM.Unlock()
+	/* Copy, so runHooks doesn't need a lock */
+	var hs []levelHook
+	if old := l.hooks.Load(); nil != old {
+		hs = append(hs, *old...)
+	}
+	hs = append(hs, levelHook{min: severity(min), fn: fn})
+	l.hooks.Store(&hs)
+}
+
+/* runHooks calls the hooks for r */
+func (l *LogSet) runHooks(r Record) {
+	hs := l.hooks.Load()
+	if nil == hs {
+		return
+	}
+	level := severity(r.Level)
+	for _, h := range *hs {
+		if level <= h.min {
+			h.fn(r)
+		}
+	}
+}
+
+// severity returns level, or LevelDebug if level is LevelDebugOnly, so that
+// lesser levels are more severe.
+func severity(level Level) Level {
+	if LevelDebugOnly == level {
+		return LevelDebug
+	}
+	return level
+}
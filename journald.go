@@ -0,0 +1,39 @@
+package easylogger
+
+/*
+ * journald.go
+ * Priority prefixes for journald
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+// journaldPriority returns the sd-daemon(3) priority prefix for a message
+// logged at level.
+func journaldPriority(level Level) string {
+	switch level {
+	case LevelVerbose:
+		return "<6>" /* Info */
+	case LevelDebug:
+		return "<7>" /* Debug */
+	default:
+		return "<2>" /* Critical, for panics */
+	}
+}
+
+// SetJournald causes the default LogSet to prefix messages with journald
+// priorities.  See LogSet.SetJournald.
+func SetJournald(on bool) { def.SetJournald(on) }
+
+// SetJournald causes each message to be prefixed with the sd-daemon(3)
+// priority for its kind if on is true, so that journalctl shows verbose
+// messages as info (<6>), debug messages as debug (<7>), and panics logged by
+// RecoverAndLog as critical (<2>).  This is meant for programs run by
+// systemd with output going to the journal.
+//
+// As journald timestamps messages itself, the logger's timestamp and other
+// flags aren't used while this is on, though its prefix is.  On platforms
+// other than Linux, SetJournald does nothing.
+func (l *LogSet) SetJournald(on bool) {
+	l.set(func(s *settings) { s.journald = on && haveJournald })
+}
@@ -0,0 +1,12 @@
+package easylogger
+
+/*
+ * journald_linux.go
+ * journald is available
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+// haveJournald is true on platforms which may have journald.
+const haveJournald = true
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package easylogger
+
+/*
+ * journald_other.go
+ * journald is not available
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+// haveJournald is true on platforms which may have journald.
+const haveJournald = false
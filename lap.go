@@ -0,0 +1,50 @@
+package easylogger
+
+/*
+ * lap.go
+ * Stopwatch-style timing
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import "time"
+
+// Lap logs the time since the previous Lap from the default LogSet.  See
+// LogSet.Lap.
+func Lap(label string) { def.Lap(label) }
+
+// LapReset restarts the default LogSet's lap timer.
+func LapReset() { def.LapReset() }
+
+// Lap logs, as a debug message, label and the time since Lap was last
+// called (or since LapReset).  The first Lap starts the timer and logs
+// label: start.  This makes for quick-and-dirty profiling:
+//
+//    ls.Lap("begin")
+//    parse()
+//    ls.Lap("parsed")    /* Logs something like "parsed: 1.5ms" */
+//    render()
+//    ls.Lap("rendered")  /* Logs something like "rendered: 20ms" */
+//
+// The timer is updated even if debug messages aren't logged, which costs
+// very little.  Each LogSet has its own timer.
+func (l *LogSet) Lap(label string) {
+	now := time.Now()
+	l.lapM.Lock()
+	last := l.lap
+	l.lap = now
+	l.lapM.Unlock()
+	if last.IsZero() {
+		l.Debug("%s: start", label)
+		return
+	}
+	l.Debug("%s: %v", label, now.Sub(last))
+}
+
+// LapReset restarts the timer used by Lap.  The next Lap starts it again.
+func (l *LogSet) LapReset() {
+	l.lapM.Lock()
+	defer l.lapM.Unlock()
+	l.lap = time.Time{}
+}
@@ -0,0 +1,190 @@
+package easylogger
+
+/*
+ * level.go
+ * Logging levels
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A Level describes which messages a LogSet logs.  Each level corresponds
+// to one of the Log* functions.
+type Level int
+
+const (
+	LevelNone      Level = iota /* Nothing is logged (LogNone) */
+	LevelVerbose                /* Verbose messages are logged (LogVerbose) */
+	LevelDebug                  /* Everything is logged (LogDebug) */
+	LevelDebugOnly              /* Debug messages are logged (LogDebugOnly) */
+)
+
+// levelNames maps accepted level names to levels, for ParseLevel.
+var levelNames = map[string]Level{
+	"none":       LevelNone,
+	"off":        LevelNone,
+	"quiet":      LevelNone,
+	"verbose":    LevelVerbose,
+	"v":          LevelVerbose,
+	"info":       LevelVerbose,
+	"debug":      LevelDebug,
+	"dbg":        LevelDebug,
+	"d":          LevelDebug,
+	"debugonly":  LevelDebugOnly,
+	"debug-only": LevelDebugOnly,
+	"debug_only": LevelDebugOnly,
+}
+
+// String returns the level's name, as accepted by ParseLevel.
+func (v Level) String() string {
+	switch v {
+	case LevelNone:
+		return "none"
+	case LevelVerbose:
+		return "verbose"
+	case LevelDebug:
+		return "debug"
+	case LevelDebugOnly:
+		return "debugonly"
+	default:
+		return fmt.Sprintf("Level(%d)", int(v))
+	}
+}
+
+// ParseLevel returns the Level named by s.  Case is ignored.  Accepted names
+// are
+//
+//    none, off, quiet                    LevelNone
+//    verbose, v, info                    LevelVerbose
+//    debug, dbg, d                       LevelDebug
+//    debugonly, debug-only, debug_only   LevelDebugOnly
+//
+// as well as the numbers 0 to 3, which are the levels in the same order.
+func ParseLevel(s string) (Level, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if v, ok := levelNames[s]; ok {
+		return v, nil
+	}
+	if n, err := strconv.Atoi(s); nil == err &&
+		int(LevelNone) <= n && int(LevelDebugOnly) >= n {
+		return Level(n), nil
+	}
+	return LevelNone, fmt.Errorf(
+		"easylogger: invalid level %q (accepted: none, off, quiet, "+
+			"verbose, v, info, debug, dbg, d, debugonly, debug-only, "+
+			"debug_only, or 0-3)",
+		s,
+	)
+}
+
+// SetLevel sets the default LogSet's level.  See LogSet.SetLevel.
+func SetLevel(level Level) { def.SetLevel(level) }
+
+// SetLevelString sets the default LogSet's level from its name.  See
+// LogSet.SetLevelString.
+func SetLevelString(s string) error { return def.SetLevelString(s) }
+
+// CurrentLevel returns the default LogSet's level.
+func CurrentLevel() Level { return def.Level() }
+
+// SetLevel calls the Log* function corresponding to level.  Invalid levels
+// are ignored, as are calls which wouldn't change the level, which keeps
+// frequent calls (e.g. on every config reload) cheap.
+func (l *LogSet) SetLevel(level Level) {
+	if level == l.Level() {
+		return
+	}
+	switch level {
+	case LevelNone:
+		l.LogNone()
+	case LevelVerbose:
+		l.LogVerbose()
+	case LevelDebug:
+		l.LogDebug()
+	case LevelDebugOnly:
+		l.LogDebugOnly()
+	}
+}
+
+// SetLevelString sets the level named by s, which is parsed with
+// ParseLevel.  This is handy for levels from config files and environment
+// variables.
+func (l *LogSet) SetLevelString(s string) error {
+	v, err := ParseLevel(s)
+	if nil != err {
+		return err
+	}
+	l.SetLevel(v)
+	return nil
+}
+
+// Level returns the level corresponding to which messages are currently
+// logged.  Setting -debug on the command line without calling any of the
+// Log* functions yields LevelDebug, as verbose messages are logged too.
+func (l *LogSet) Level() Level {
+	return levelOf(l.switches())
+}
+
+// levelOf returns the Level corresponding to the verbose and debug switches
+// and whether they've been changed by one of the Log* functions.
+func levelOf(v, d, c bool) Level {
+	v = v || (!c && d) /* See Verbose */
+	switch {
+	case v && d:
+		return LevelDebug
+	case v:
+		return LevelVerbose
+	case d:
+		return LevelDebugOnly
+	default:
+		return LevelNone
+	}
+}
+
+// SetOnLevelChange causes f to be called with the old and new levels whenever
+// l's level is changed by one of the Log* functions, SetLevel, command-line
+// flags, or the like.  Calls which leave the level as it was don't cause f
+// to be called.  This allows for keeping track of who changed the level, and
+// when.  f is called synchronously with a lock held, so it must not call l's
+// Log* or SetLevel* methods.  f may be nil to stop calling the previous
+// function.
+func (l *LogSet) SetOnLevelChange(f func(old, new Level)) {
+	l.sm.Lock()
+	defer l.sm.Unlock()
+	l.onChange = f
+}
+
+// Enabled reports whether the default LogSet would log a message at level.
+// See LogSet.Enabled.
+func Enabled(level Level) bool { return def.Enabled(level) }
+
+// Enabled reports whether a message at level (LevelVerbose or LevelDebug)
+// would be logged, which is handy for skipping expensive work when it won't
+// be:
+//
+//    if ls.Enabled(easylogger.LevelDebug) {
+//            ls.Debug("State: %v", expensiveDump())
+//    }
+//
+// Enabled consults the level set with the Log* functions, SetLevel, or
+// command-line flags, and the quiet window set with SetQuietWindow.  For a
+// LogSet from NewMulti, it reports whether any of the LogSets passed to
+// NewMulti would log the message.  LevelNone messages, such as panics, are
+// always logged.  Enabled doesn't take any locks.
+func (l *LogSet) Enabled(level Level) bool {
+	if 0 == len(l.children) {
+		return l.logs(level)
+	}
+	for _, c := range l.children {
+		if c.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package easylogger
+
+/*
+ * level_test.go
+ * Tests for levels
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	for _, c := range []struct {
+		s    string
+		want Level
+	}{
+		{"none", LevelNone},
+		{"off", LevelNone},
+		{"quiet", LevelNone},
+		{"NONE", LevelNone},
+		{"Off", LevelNone},
+		{"0", LevelNone},
+		{"verbose", LevelVerbose},
+		{"v", LevelVerbose},
+		{"info", LevelVerbose},
+		{"VERBOSE", LevelVerbose},
+		{"Info", LevelVerbose},
+		{" v ", LevelVerbose},
+		{"1", LevelVerbose},
+		{"debug", LevelDebug},
+		{"dbg", LevelDebug},
+		{"d", LevelDebug},
+		{"DEBUG", LevelDebug},
+		{"DbG", LevelDebug},
+		{"2", LevelDebug},
+		{"debugonly", LevelDebugOnly},
+		{"debug-only", LevelDebugOnly},
+		{"debug_only", LevelDebugOnly},
+		{"DebugOnly", LevelDebugOnly},
+		{"DEBUG-ONLY", LevelDebugOnly},
+		{"3", LevelDebugOnly},
+	} {
+		got, err := ParseLevel(c.s)
+		if nil != err {
+			t.Errorf("ParseLevel(%q): %v", c.s, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("ParseLevel(%q): got %v, want %v", c.s, got, c.want)
+		}
+	}
+}
+
+func TestParseLevelInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"loud",
+		"debugg",
+		"-1",
+		"4",
+		"1.0",
+		"verbose debug",
+	} {
+		if got, err := ParseLevel(s); nil == err {
+			t.Errorf("ParseLevel(%q): got %v, want an error", s, got)
+		}
+	}
+}
+
+func TestParseLevelString(t *testing.T) {
+	for _, level := range []Level{
+		LevelNone,
+		LevelVerbose,
+		LevelDebug,
+		LevelDebugOnly,
+	} {
+		got, err := ParseLevel(level.String())
+		if nil != err {
+			t.Errorf("ParseLevel(%q): %v", level.String(), err)
+			continue
+		}
+		if got != level {
+			t.Errorf("ParseLevel(%q): got %v", level.String(), got)
+		}
+	}
+}
+
+func TestOnLevelChangeOnlyOnChange(t *testing.T) {
+	l := New()
+	var changes [][2]Level
+	l.SetOnLevelChange(func(old, new Level) {
+		changes = append(changes, [2]Level{old, new})
+	})
+	for _, level := range []Level{
+		LevelNone, /* Already none */
+		LevelVerbose,
+		LevelVerbose,
+		LevelDebug,
+		LevelDebug,
+		LevelDebug,
+		LevelDebugOnly,
+		LevelNone,
+		LevelNone,
+	} {
+		l.SetLevel(level)
+	}
+	l.LogNone()
+	want := [][2]Level{
+		{LevelNone, LevelVerbose},
+		{LevelVerbose, LevelDebug},
+		{LevelDebug, LevelDebugOnly},
+		{LevelDebugOnly, LevelNone},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Got %d changes %v, want %v", len(changes), changes, want)
+	}
+	for i, c := range changes {
+		if c != want[i] {
+			t.Errorf("Change %d: got %v, want %v", i, c, want[i])
+		}
+	}
+}
+
+func TestOnLevelChangeFlags(t *testing.T) {
+	l := New()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	l.RegisterFlagsWithUsage(fs, "verbose", "debug")
+	var n int
+	l.SetOnLevelChange(func(old, new Level) { n++ })
+	if err := fs.Parse([]string{
+		"-verbose",
+		"-verbose=true",
+		"-debug",
+	}); nil != err {
+		t.Fatalf("Parse: %v", err)
+	}
+	if 2 != n {
+		t.Errorf("Got %d calls, want 2", n)
+	}
+}
+
+func TestWithLevel(t *testing.T) {
+	for _, level := range []Level{
+		LevelVerbose,
+		LevelDebug,
+		LevelDebugOnly,
+		LevelNone,
+	} {
+		l := New()
+		WithLevel(level)(l)
+		if got := l.Level(); got != level {
+			t.Errorf("WithLevel(%v): got %v", level, got)
+		}
+	}
+}
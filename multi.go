@@ -0,0 +1,44 @@
+package easylogger
+
+/*
+ * multi.go
+ * Log to several LogSets at once
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+// NewMulti returns a LogSet which passes every message to each of sets, in
+// order, for example to log to both a local file and a remote collector.
+// Each of sets decides for itself whether to log a message, and how, so
+// each may have its own level, logger, and so on.
+//
+//    local, remote := easylogger.New(), easylogger.New()
+//    local.LogDebug()
+//    remote.LogVerbose()
+//    /* ... */
+//    ls := easylogger.NewMulti(local, remote)
+//    ls.Debug("Only logged locally")
+//
+// Settings made on the returned LogSet itself, such as its level or logger,
+// have no effect on which messages are logged or how.  Messages added to a
+// Batch are passed on as they're added, rather than all at once.
+func NewMulti(sets ...*LogSet) *LogSet {
+	l := New()
+	l.children = append([]*LogSet(nil), sets...)
+	return l
+}
+
+// forward passes a message at level, with tags, to each of l's children.  It
+// returns true if any of them wrote it.
+func (l *LogSet) forward(level Level, tags []string, format string,
+	args ...interface{}) bool {
+	format = l.opPrefix + format
+	var written bool
+	for _, c := range l.children {
+		if c.logTagged(level, tags, format, args...) {
+			written = true
+		}
+	}
+	return written
+}
@@ -0,0 +1,87 @@
+package easylogger
+
+/*
+ * once.go
+ * Log once per call site
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// maxOnceSites is the most call sites DebugOnceCaller will remember.
+const maxOnceSites = 4096
+
+// DebugOnceCaller logs a debugging message from the default LogSet at most
+// once per call site.  See LogSet.DebugOnceCaller.
+func DebugOnceCaller(format string, args ...interface{}) {
+	def.debugOnceCaller(format, args...)
+}
+
+// ResetOnce re-arms every call site of the default LogSet's DebugOnceCaller.
+func ResetOnce() { def.ResetOnce() }
+
+// DebugOnceCaller is like Debug, but logs at most once per call site, as
+// identified by the file and line from which it was called.  This is handy
+// for deprecation warnings and the like:
+//
+//    func OldThing() {
+//            ls.DebugOnceCaller("OldThing is deprecated")
+//            /* ... */
+//    }
+//
+// A call site is only remembered once its message has been written; calls
+// made while debug logging is off, during a quiet window, or whose message
+// is dropped by a processor don't count.  As the call site is checked and
+// remembered with a lock held, the message mustn't cause DebugOnceCaller to
+// be called again, e.g. from a level hook.  At most 4096 call sites are
+// remembered, after which messages from new call sites are logged every
+// time.  ResetOnce re-arms all call sites.  DebugOnceCaller is safe for
+// concurrent use.
+func (l *LogSet) DebugOnceCaller(format string, args ...interface{}) {
+	l.debugOnceCaller(format, args...)
+}
+
+// debugOnceCaller does the work for both DebugOnceCaller functions, which
+// must call it directly so the caller is always two frames up.
+func (l *LogSet) debugOnceCaller(format string, args ...interface{}) {
+	l.check()
+	/* Don't bother if debugging is off */
+	if !l.Enabled(LevelDebug) {
+		return
+	}
+	/* Work out where we were called from */
+	_, file, line, ok := runtime.Caller(2)
+	if !ok {
+		l.log(LevelDebug, format, args...)
+		return
+	}
+	site := fmt.Sprintf("%s:%d", file, line)
+	l.onceM.Lock()
+	defer l.onceM.Unlock()
+	if _, ok := l.once[site]; ok {
+		return
+	}
+	/* Only remember the site if the message got out */
+	if !l.log(LevelDebug, format, args...) {
+		return
+	}
+	if nil == l.once {
+		l.once = make(map[string]struct{})
+	}
+	if maxOnceSites > len(l.once) {
+		l.once[site] = struct{}{}
+	}
+}
+
+// ResetOnce re-arms every call site of DebugOnceCaller, so each will log
+// once more.
+func (l *LogSet) ResetOnce() {
+	l.onceM.Lock()
+	defer l.onceM.Unlock()
+	l.once = nil
+}
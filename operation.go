@@ -0,0 +1,44 @@
+package easylogger
+
+/*
+ * operation.go
+ * LogSets for a single operation
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithOperation returns a LogSet for an operation logged by the default
+// LogSet.  See LogSet.WithOperation.
+func WithOperation(op string) (*LogSet, func()) { return def.WithOperation(op) }
+
+// WithOperation returns a LogSet which logs with l, putting op=op before
+// every message, and a function to call when the operation's done, which
+// logs how long it took as a debugging message.
+//
+//    ols, done := ls.WithOperation("import-" + id)
+//    defer done()
+//    ols.Verbose("Importing %v", file)  /* op=import-12 Importing x.csv */
+//
+// As with NewMulti, l decides whether and how messages are logged, and
+// settings made on the returned LogSet have no effect.  Operations may be
+// nested, in which case each op field is logged, outermost first.  Only the
+// first call to the returned function logs anything.
+func (l *LogSet) WithOperation(op string) (*LogSet, func()) {
+	start := time.Now()
+	ol := NewMulti(l)
+	/* forward puts this in front of the format string */
+	ol.opPrefix = "op=" + strings.ReplaceAll(op, "%", "%%") + " "
+	var once sync.Once
+	return ol, func() {
+		once.Do(func() {
+			ol.Debug("done in %v", time.Since(start))
+		})
+	}
+}
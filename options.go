@@ -0,0 +1,127 @@
+package easylogger
+
+/*
+ * options.go
+ * Options for GenerateOpts
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"flag"
+	"io"
+	"log"
+	"strconv"
+)
+
+// An Option configures the default LogSet when passed to GenerateOpts.
+type Option func(*LogSet)
+
+// GenerateOpts is like Generate, but the default LogSet is configured by
+// opts, which are applied in order, before the verbose and debug functions
+// are returned.
+//
+//    var verbose, debug = easylogger.GenerateOpts(
+//            easylogger.WithFlags(),
+//            easylogger.WithOutput(os.Stdout),
+//    )
+//
+// GenerateOpts() with no options is the same as Generate(false), and
+// GenerateOpts(WithFlags()) is the same as Generate(true).
+func GenerateOpts(opts ...Option) (verbose,
+	debug func(format string, args ...interface{})) {
+	for _, opt := range opts {
+		opt(def)
+	}
+	return def.Verbose, def.Debug
+}
+
+// WithFlags adds -verbose and -debug to the default set of flags, as with
+// Generate(true).
+func WithFlags() Option {
+	return func(l *LogSet) {
+		l.RegisterFlagsWithUsage(
+			flag.CommandLine,
+			"Log verbosely",
+			"Log debugging messages",
+		)
+	}
+}
+
+// RegisterFlagsWithUsage adds -verbose and -debug to fs with the given usage
+// strings, for the default LogSet.  See LogSet.RegisterFlagsWithUsage.
+func RegisterFlagsWithUsage(fs *flag.FlagSet, verboseUsage, debugUsage string) {
+	def.RegisterFlagsWithUsage(fs, verboseUsage, debugUsage)
+}
+
+// RegisterFlagsWithUsage adds -verbose and -debug to fs, which turn on
+// verbose and debug logging as they do when added by Generate(true), but
+// with the given usage strings in the help output.
+//
+//    ls.RegisterFlagsWithUsage(
+//            flag.CommandLine,
+//            "Explain what's going on",
+//            "Explain what's going on in excruciating detail",
+//    )
+func (l *LogSet) RegisterFlagsWithUsage(fs *flag.FlagSet, verboseUsage,
+	debugUsage string) {
+	l.setSwitch(verboseBit, false)
+	l.setSwitch(debugBit, false)
+	fs.Var(switchFlag{l, verboseBit}, "verbose", verboseUsage)
+	fs.Var(switchFlag{l, debugBit}, "debug", debugUsage)
+}
+
+// switchFlag is a boolean flag.Value which sets one of a LogSet's switches.
+type switchFlag struct {
+	l   *LogSet
+	bit uint32
+}
+
+/* String returns the switch's state, or false for the zero value */
+func (f switchFlag) String() string {
+	if nil == f.l {
+		return "false"
+	}
+	return strconv.FormatBool(0 != f.l.state.Load()&f.bit)
+}
+
+/* Set turns the switch on or off */
+func (f switchFlag) Set(s string) error {
+	on, err := strconv.ParseBool(s)
+	if nil != err {
+		return err
+	}
+	f.l.setSwitch(f.bit, on)
+	return nil
+}
+
+/* IsBoolFlag allows the flag to be given without a value */
+func (f switchFlag) IsBoolFlag() bool { return true }
+
+// WithLogger causes logger to be used for log output, as with SetLogger.
+func WithLogger(logger *log.Logger) Option {
+	return func(l *LogSet) { l.SetLogger(logger) }
+}
+
+// WithOutput causes log output to be written to w, with the same flags as
+// the standard logger has by default (log.LstdFlags).
+func WithOutput(w io.Writer) Option {
+	return func(l *LogSet) { l.SetLogger(log.New(w, "", log.LstdFlags)) }
+}
+
+// WithVerbose turns on verbose logging, as with LogVerbose.
+func WithVerbose() Option {
+	return func(l *LogSet) { l.LogVerbose() }
+}
+
+// WithDebug turns on debug logging, as with LogDebug.
+func WithDebug() Option {
+	return func(l *LogSet) { l.LogDebug() }
+}
+
+// WithLevel sets the level, as with SetLevel.  Unlike WithVerbose and
+// WithDebug, it can also be used for LevelDebugOnly or LevelNone.
+func WithLevel(level Level) Option {
+	return func(l *LogSet) { l.SetLevel(level) }
+}
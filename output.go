@@ -0,0 +1,68 @@
+package easylogger
+
+/*
+ * output.go
+ * Extra outputs, each with its own format
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"io"
+	"log"
+)
+
+// formattedOutput is an extra output, from AddFormattedOutput.
+type formattedOutput struct {
+	w      io.Writer
+	format func(Record) []byte
+}
+
+// AddFormattedOutput causes messages from the default LogSet to also be
+// written to w, formatted by format.  See LogSet.AddFormattedOutput.
+func AddFormattedOutput(w io.Writer, format func(Record) []byte) {
+	def.AddFormattedOutput(w, format)
+}
+
+// AddFormattedOutput causes every message l logs to also be written to w,
+// rendered by format, independently of how it's rendered for the logger.
+// This allows, for example, readable text on the terminal and records
+// written by BinaryFormat to a file:
+//
+//    ls.SetLogger(log.New(os.Stderr, "", log.LstdFlags))
+//    ls.AddFormattedOutput(f, easylogger.BinaryFormat)
+//
+// As with SetFormatFunc, the bytes format returns are written as-is.  If
+// format is nil, messages are written to w as the logger would write them,
+// with its prefix and flags.  Each output renders each message again, which
+// costs CPU time in proportion to the number of outputs.  Outputs are
+// written after the logger, in the order they were added, while l's lock is
+// held.  Reset removes all outputs.
+func (l *LogSet) AddFormattedOutput(w io.Writer, format func(Record) []byte) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.outputs = append(l.outputs, formattedOutput{w: w, format: format})
+}
+
+// writeOutputs writes r to the outputs, according to s.  l.m must be
+// held.
+func (l *LogSet) writeOutputs(s *settings, r Record) {
+	for _, o := range l.outputs {
+		l.writeTo(s, o.w, o.format, r)
+	}
+}
+
+// writeTo writes r to w, rendered with format, or as the logger would
+// according to s if format is nil.
+func (l *LogSet) writeTo(s *settings, w io.Writer,
+	format func(Record) []byte, r Record) {
+	unlock := s.lockWriter(w, true)
+	defer unlock()
+	if nil != format {
+		w.Write(format(r))
+		return
+	}
+	t := l.target()
+	s.printText(log.New(w, t.Prefix(), t.Flags()), r, true)
+}
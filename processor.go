@@ -0,0 +1,64 @@
+package easylogger
+
+/*
+ * processor.go
+ * Functions which change records before they're written
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+// AddProcessor causes fn to be called with every message logged by the
+// default LogSet before it's written.  See LogSet.AddProcessor.
+func AddProcessor(fn func(*Record)) { def.AddProcessor(fn) }
+
+// AddProcessor causes fn to be called with every message l logs, before it's
+// written.  fn may change the record, or set its Drop field to stop it being
+// logged.  For example, to add a trace ID and drop health checks:
+//
+//    ls.AddProcessor(func(r *easylogger.Record) {
+//            r.Message = "trace=" + traceID() + " " + r.Message
+//    })
+//    ls.AddProcessor(func(r *easylogger.Record) {
+//            r.Drop = strings.Contains(r.Message, "/healthz")
+//    })
+//
+// Processors are called in the order they were added, after the message has
+// been formatted (including by SetMessageTransform, SetPrefixFunc and the
+// like) and after messages which aren't logged because of the level or
+// SetQuietWindow have been filtered out.  Once a processor drops a record,
+// later processors aren't called.  Records which aren't dropped are then
+// written and passed to hooks from AddLevelHook and subscribers, which see
+// any changes made by processors.  fn is called synchronously, so should be
+// quick, and must not log with l.
+func (l *LogSet) AddProcessor(fn func(*Record)) {
+	l.procM.Lock()
+	defer l.procM.Unlock()
+	/* Copy, so process doesn't need a lock */
+	var ps []func(*Record)
+	if old := l.procs.Load(); nil != old {
+		ps = append(ps, *old...)
+	}
+	ps = append(ps, fn)
+	l.procs.Store(&ps)
+}
+
+// process returns r after running the processors on it.  Without any
+// processors, r doesn't escape to the heap.
+func (l *LogSet) process(r Record) Record {
+	ps := l.procs.Load()
+	if nil == ps {
+		return r
+	}
+	return runProcessors(r, *ps)
+}
+
+/* runProcessors runs ps on r, and returns r */
+func runProcessors(r Record, ps []func(*Record)) Record {
+	for _, p := range ps {
+		if p(&r); r.Drop {
+			break
+		}
+	}
+	return r
+}
@@ -0,0 +1,52 @@
+package easylogger
+
+/*
+ * promote.go
+ * Log some debugging messages as verbose messages
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import "math/rand/v2"
+
+// SetDebugPromotion causes the default LogSet to log pct percent of
+// debugging messages as verbose messages.  See LogSet.SetDebugPromotion.
+func SetDebugPromotion(pct int) { def.SetDebugPromotion(pct) }
+
+// SetDebugPromotion causes a random pct percent of debugging messages which
+// wouldn't otherwise be logged to be logged as verbose messages, if verbose
+// messages are logged, which gives a sample of debugging detail during an
+// incident without turning on debugging messages.  Whether a message is
+// promoted is decided afresh for each message.  Promoted messages are
+// logged with LevelVerbose, so hooks from AddLevelHook, SetJournald and the
+// like treat them as verbose messages.  pct is clamped to between 0 and
+// 100; 0, the default, turns promotion off.  Promotion doesn't affect
+// Enabled, which reports whether messages at a level are always logged.
+func (l *LogSet) SetDebugPromotion(pct int) {
+	l.promote.Store(int32(min(max(pct, 0), 100)))
+}
+
+// gate returns the level at which a message at level should be logged, and
+// whether it should be logged at all.
+func (l *LogSet) gate(level Level) (Level, bool) {
+	if l.logs(level) {
+		return level, true
+	}
+	if l.promoted(level) {
+		return LevelVerbose, true
+	}
+	return level, false
+}
+
+/* promoted returns true if a message at level should be promoted */
+func (l *LogSet) promoted(level Level) bool {
+	if LevelDebug != severity(level) {
+		return false
+	}
+	pct := l.promote.Load()
+	if 0 == pct || !l.logs(LevelVerbose) {
+		return false
+	}
+	return rand.Int32N(100) < pct
+}
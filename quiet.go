@@ -0,0 +1,76 @@
+package easylogger
+
+/*
+ * quiet.go
+ * Quiet hours
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"errors"
+	"time"
+)
+
+// day is how long a day is, for SetQuietWindow.
+const day = 24 * time.Hour
+
+// quietWindow is a time of day during which only panics are logged.
+type quietWindow struct {
+	start time.Duration /* Since midnight */
+	end   time.Duration /* Since midnight */
+}
+
+/* contains returns true if t's time of day is in the window */
+func (w *quietWindow) contains(t time.Time) bool {
+	h, m, s := t.Clock()
+	d := time.Duration(h)*time.Hour +
+		time.Duration(m)*time.Minute +
+		time.Duration(s)*time.Second +
+		time.Duration(t.Nanosecond())
+	if w.start <= w.end {
+		return w.start <= d && d < w.end
+	}
+	/* Spans midnight */
+	return w.start <= d || d < w.end
+}
+
+// SetQuietWindow sets the default LogSet's quiet hours.  See
+// LogSet.SetQuietWindow.
+func SetQuietWindow(start, end time.Duration) error {
+	return def.SetQuietWindow(start, end)
+}
+
+// SetQuietWindow causes verbose and debug messages not to be logged between
+// start and end, which are times of day in local time, given as durations
+// since midnight.  Panics logged by RecoverAndLog are still logged.  If start
+// is after end, the window spans midnight.  For example, to quiet things
+// down overnight:
+//
+//    ls.SetQuietWindow(22*time.Hour, 6*time.Hour)
+//
+// Outside the window, what's logged is governed by the level as usual;
+// inside the window, the window takes precedence.  Times of 24 hours or more
+// are taken to be on the next day, so 22*time.Hour to 30*time.Hour is the
+// same as the above.  Setting start equal to end, once reduced to a time of
+// day, removes the window.  An error is returned, and the window isn't
+// changed, if start or end is negative.
+func (l *LogSet) SetQuietWindow(start, end time.Duration) error {
+	if 0 > start || 0 > end {
+		return errors.New("easylogger: negative quiet window time")
+	}
+	start, end = start%day, end%day
+	if start == end {
+		l.quiet.Store(nil)
+		return nil
+	}
+	l.quiet.Store(&quietWindow{start: start, end: end})
+	return nil
+}
+
+/* inQuietWindow returns true if it's currently quiet hours */
+func (l *LogSet) inQuietWindow() bool {
+	w := l.quiet.Load()
+	return nil != w && w.contains(time.Now())
+}
@@ -0,0 +1,81 @@
+package easylogger
+
+/*
+ * quiet_test.go
+ * Tests for quiet hours
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietWindowContains(t *testing.T) {
+	h := time.Hour
+	for _, c := range []struct {
+		start, end time.Duration
+		at         time.Duration /* Time of day */
+		want       bool
+	}{
+		/* Same day */
+		{9 * h, 17 * h, 12 * h, true},
+		{9 * h, 17 * h, 9 * h, true},
+		{9 * h, 17 * h, 17 * h, false},
+		{9 * h, 17 * h, 8 * h, false},
+		{9 * h, 17 * h, 23 * h, false},
+		/* Spanning midnight */
+		{22 * h, 6 * h, 23 * h, true},
+		{22 * h, 6 * h, 0, true},
+		{22 * h, 6 * h, 2 * h, true},
+		{22 * h, 6 * h, 22 * h, true},
+		{22 * h, 6 * h, 6 * h, false},
+		{22 * h, 6 * h, 12 * h, false},
+		{22 * h, 6 * h, 21*h + 59*time.Minute, false},
+		/* Given past midnight */
+		{22 * h, 30 * h, 2 * h, true},
+		{22 * h, 30 * h, 23 * h, true},
+		{22 * h, 30 * h, 12 * h, false},
+		{26 * h, 28 * h, 3 * h, true},
+		{26 * h, 28 * h, 5 * h, false},
+	} {
+		l := New()
+		if err := l.SetQuietWindow(c.start, c.end); nil != err {
+			t.Fatalf("SetQuietWindow(%v, %v): %v", c.start, c.end, err)
+		}
+		at := time.Date(2012, 1, 1, 0, 0, 0, 0, time.Local).Add(c.at)
+		if got := l.quiet.Load().contains(at); got != c.want {
+			t.Errorf(
+				"Window %v-%v at %v: got %v, want %v",
+				c.start,
+				c.end,
+				c.at,
+				got,
+				c.want,
+			)
+		}
+	}
+}
+
+func TestQuietWindowInvalid(t *testing.T) {
+	l := New()
+	l.SetQuietWindow(time.Hour, 2*time.Hour)
+	for _, c := range [][2]time.Duration{
+		{-time.Hour, time.Hour},
+		{time.Hour, -time.Hour},
+	} {
+		if nil == l.SetQuietWindow(c[0], c[1]) {
+			t.Errorf("SetQuietWindow(%v, %v): no error", c[0], c[1])
+		}
+	}
+	if w := l.quiet.Load(); nil == w || time.Hour != w.start {
+		t.Errorf("Window changed after error")
+	}
+	/* Equal times of day remove the window */
+	l.SetQuietWindow(time.Hour, 25*time.Hour)
+	if nil != l.quiet.Load() {
+		t.Errorf("Window not removed")
+	}
+}
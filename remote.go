@@ -0,0 +1,244 @@
+package easylogger
+
+/*
+ * remote.go
+ * Log to a remote collector
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"log"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defaults for SetRemote.
+const (
+	defaultRemoteBuffer     = 1024
+	defaultRemoteMinBackoff = 100 * time.Millisecond
+	defaultRemoteMaxBackoff = 30 * time.Second
+	defaultRemoteTimeout    = 10 * time.Second
+	minRemoteBackoff        = time.Millisecond /* Least WithRemoteBackoff */
+)
+
+// A RemoteOption configures SetRemote.
+type RemoteOption func(*remoteWriter)
+
+// WithRemoteBuffer sets the number of messages which may be held while
+// disconnected.  Once n messages are held, new messages are dropped.  The
+// default is 1024.  A negative n is treated as 0, which drops messages
+// unless they can be sent straight away.
+func WithRemoteBuffer(n int) RemoteOption {
+	if 0 > n {
+		n = 0
+	}
+	return func(w *remoteWriter) { w.bufSize = n }
+}
+
+// WithRemoteBackoff sets how long to wait before trying to reconnect.  The
+// wait starts at min and doubles after every failure, up to max.  The
+// defaults are 100ms and 30s.  A min of less than 1ms is treated as 1ms, so
+// as not to redial in a tight loop, and a max less than min as min.
+func WithRemoteBackoff(min, max time.Duration) RemoteOption {
+	if minRemoteBackoff > min {
+		min = minRemoteBackoff
+	}
+	if min > max {
+		max = min
+	}
+	return func(w *remoteWriter) { w.minWait, w.maxWait = min, max }
+}
+
+// WithRemoteTimeout sets how long connecting and sending each message may
+// take before the attempt is abandoned, so that an unresponsive collector
+// can't hold up Close.  The default is 10s.
+func WithRemoteTimeout(d time.Duration) RemoteOption {
+	return func(w *remoteWriter) { w.timeout = d }
+}
+
+// remoteWriter sends messages to a remote address, reconnecting as needed.
+// Writes are queued and never block.
+type remoteWriter struct {
+	network string
+	addr    string
+	bufSize int
+	minWait time.Duration
+	maxWait time.Duration
+	timeout time.Duration
+
+	conn     net.Conn
+	ch       chan []byte   /* Queued messages */
+	done     chan struct{} /* Closed by Close */
+	finished chan struct{} /* Closed when run returns */
+	dropped  atomic.Uint64 /* Messages dropped with a full queue */
+	once     sync.Once
+}
+
+/* Write queues a copy of p to be sent, or drops it if the queue's full */
+func (w *remoteWriter) Write(p []byte) (int, error) {
+	b := make([]byte, len(p))
+	copy(b, p)
+	select {
+	case w.ch <- b:
+	default:
+		w.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// run sends queued messages until w.done is closed, reconnecting with
+// backoff after failures.
+func (w *remoteWriter) run() {
+	defer close(w.finished)
+	var (
+		msg  []byte
+		wait = w.minWait
+	)
+	for {
+		/* Get a message to send if we don't have one */
+		if nil == msg {
+			select {
+			case msg = <-w.ch:
+			case <-w.done:
+				w.drain()
+				return
+			}
+		}
+		if nil == w.send(msg) {
+			msg = nil
+			wait = w.minWait
+			continue
+		}
+		/* Wait a bit before trying again */
+		select {
+		case <-time.After(wait):
+		case <-w.done:
+			w.drain()
+			return
+		}
+		if wait *= 2; wait > w.maxWait {
+			wait = w.maxWait
+		}
+	}
+}
+
+// drain sends what's left in the queue, stopping at the first failure, and
+// closes the connection.
+func (w *remoteWriter) drain() {
+	defer func() {
+		if nil != w.conn {
+			w.conn.Close()
+		}
+	}()
+	for {
+		select {
+		case msg := <-w.ch:
+			if nil != w.send(msg) {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+/* send sends msg, connecting first if need be */
+func (w *remoteWriter) send(msg []byte) error {
+	if nil == w.conn {
+		c, err := net.DialTimeout(w.network, w.addr, w.timeout)
+		if nil != err {
+			return err
+		}
+		w.conn = c
+	}
+	if 0 < w.timeout {
+		w.conn.SetWriteDeadline(time.Now().Add(w.timeout))
+	}
+	if _, err := w.conn.Write(msg); nil != err {
+		w.conn.Close()
+		w.conn = nil
+		return err
+	}
+	return nil
+}
+
+/* Close sends what it can of the queue and closes the connection */
+func (w *remoteWriter) Close() error {
+	w.once.Do(func() { close(w.done) })
+	<-w.finished
+	return nil
+}
+
+// SetRemote causes the default LogSet to log to a remote address.  See
+// LogSet.SetRemote.
+func SetRemote(network, addr string, opts ...RemoteOption) error {
+	return def.SetRemote(network, addr, opts...)
+}
+
+// SetRemote causes l to log to addr on the named network (see net.Dial),
+// e.g. a TCP log collector.  The address is dialed before SetRemote returns,
+// and an error is returned if that fails.  Afterwards, a failed write causes
+// the connection to be redialed, with backoff (see WithRemoteBackoff).
+// Messages logged while disconnected are held until they can be sent, up to
+// a limit (see WithRemoteBuffer), after which they're dropped and counted
+// (see RemoteDropped).  Sending happens in the background; logging never
+// waits on the network.
+//
+// Connecting and sending each message give up after a timeout (see
+// WithRemoteTimeout), so Close can't hang on an unresponsive collector.
+// Messages have the same prefix and flags as the logger in use beforehand.
+// Close sends what it can of any held messages and closes the connection.
+// SetRemote replaces the logger in use, as with SetLogger.
+func (l *LogSet) SetRemote(network, addr string, opts ...RemoteOption) error {
+	w := &remoteWriter{
+		network:  network,
+		addr:     addr,
+		bufSize:  defaultRemoteBuffer,
+		minWait:  defaultRemoteMinBackoff,
+		maxWait:  defaultRemoteMaxBackoff,
+		timeout:  defaultRemoteTimeout,
+		done:     make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.ch = make(chan []byte, w.bufSize)
+	/* Make sure we can connect at all */
+	c, err := net.DialTimeout(network, addr, w.timeout)
+	if nil != err {
+		return err
+	}
+	w.conn = c
+	go w.run()
+	/* Replace the old logger and remote */
+	t := l.target()
+	l.SetLogger(log.New(w, t.Prefix(), t.Flags()))
+	if old := l.remote.Swap(w); nil != old {
+		old.Close()
+	}
+	return nil
+}
+
+// RemoteDropped returns the number of messages dropped because they couldn't
+// be held for SetRemote.
+func (l *LogSet) RemoteDropped() uint64 {
+	w := l.remote.Load()
+	if nil == w {
+		return 0
+	}
+	return w.dropped.Load()
+}
+
+/* closeRemote closes l's remote writer, if it has one */
+func (l *LogSet) closeRemote() error {
+	w := l.remote.Swap(nil)
+	if nil == w {
+		return nil
+	}
+	return w.Close()
+}
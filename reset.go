@@ -0,0 +1,68 @@
+package easylogger
+
+/*
+ * reset.go
+ * Put a LogSet back the way New made it
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import "time"
+
+// Reset puts the default LogSet back the way it was when the program
+// started.  See LogSet.Reset.
+func Reset() { def.Reset() }
+
+// Reset puts l back the way New made it, which is handy for cleaning up
+// after tests when l is shared.  First, l is closed as by Close, which
+// flushes buffered output and stops heartbeats.  Then nothing is logged but
+// panics, messages go to the standard logger, and everything set with
+// SetMessageTransform, SetHostField, AddLevelHook, AddProcessor,
+// SetTagRoute, AddFormattedOutput, SetQuietWindow, SetOnLevelChange and the
+// like is forgotten, as are the time zone from SetUTC or SetTimezone, the
+// prefix from Apply, and the file and line added by RegisterVerbosityFlag's
+// flag.  Sequence numbers start again from 1, uptimes from the call to
+// Reset, and the state of DebugOnceCaller, Lap, and LogEvery is cleared.
+//
+// None of l's settings are kept in the logger in use, so the logger itself
+// is left as it is; the functions returned by CaptureStdLog and
+// InstallAsSlogDefault put back the standard logger.
+//
+// Subscriptions from SubscribeBlocking are left alone, as they belong to
+// their subscribers, as are the LogSets passed to NewMulti.  Errors from
+// Close are discarded; call Close first to see them.  Messages logged
+// during Reset may or may not be affected by it.
+func (l *LogSet) Reset() {
+	l.check()
+	l.Close()
+	/* Changes to the switches notify the old function, if any */
+	l.setSwitches(false, false, false)
+	l.sm.Lock()
+	l.onChange = nil
+	l.levelVar = nil
+	l.sm.Unlock()
+
+	l.set(func(s *settings) { *s = settings{start: time.Now()} })
+	l.logger.Store(nil)
+	l.seq.Store(0)
+	l.quiet.Store(nil)
+	l.promote.Store(0)
+	l.m.Lock()
+	l.routes = nil
+	l.outputs = nil
+	l.m.Unlock()
+
+	l.hookM.Lock()
+	l.hooks.Store(nil)
+	l.hookM.Unlock()
+	l.procM.Lock()
+	l.procs.Store(nil)
+	l.procM.Unlock()
+	l.hist.Store(nil)
+	l.LapReset()
+	l.ResetOnce()
+	l.everyM.Lock()
+	l.every = nil
+	l.everyM.Unlock()
+}
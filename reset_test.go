@@ -0,0 +1,70 @@
+package easylogger
+
+/*
+ * reset_test.go
+ * Tests for Reset
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"bytes"
+	"flag"
+	"log"
+	"testing"
+	"time"
+)
+
+func TestResetLikeNew(t *testing.T) {
+	var b bytes.Buffer
+	lg := log.New(&b, "lg: ", log.Ltime)
+
+	/* Change as much as we can */
+	l := New()
+	l.SetLogger(lg)
+	if err := l.Apply(Config{
+		Level:    "debug",
+		Prefix:   "app: ",
+		UTC:      true,
+		Sequence: true,
+		Host:     true,
+	}); nil != err {
+		t.Fatalf("Apply: %v", err)
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	l.RegisterVerbosityFlag(fs, "v")
+	if err := fs.Parse([]string{"-v", "-v", "-v"}); nil != err {
+		t.Fatalf("Parse: %v", err)
+	}
+	l.SetBuffered(1024, time.Hour)
+	l.SetTimezone(time.FixedZone("test", 3600))
+	l.Debug("before")
+	l.Reset()
+
+	/* The logger should be as it was */
+	if got := lg.Prefix(); "lg: " != got {
+		t.Errorf("Prefix: got %q, want %q", got, "lg: ")
+	}
+	if got := lg.Flags(); log.Ltime != got {
+		t.Errorf("Flags: got %d, want %d", got, log.Ltime)
+	}
+	if lg.Writer() != &b {
+		t.Errorf("Writer changed")
+	}
+	if !l.ConfigEqual(New()) {
+		t.Errorf("Config differs from New: %q", l.ConfigDiff(New()))
+	}
+
+	/* And l should log like a new LogSet */
+	logOne := func(l *LogSet) string {
+		b.Reset()
+		l.SetLogger(log.New(&b, "", 0))
+		l.LogDebug()
+		l.Debug("message")
+		return b.String()
+	}
+	if got, want := logOne(l), logOne(New()); got != want {
+		t.Errorf("Output: got %q, want %q", got, want)
+	}
+}
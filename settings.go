@@ -0,0 +1,70 @@
+package easylogger
+
+/*
+ * settings.go
+ * Settings read while logging
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"log"
+	"time"
+)
+
+// settings are the parts of a LogSet's configuration used to log a message.
+// As with the switches, they're read without locking while logging, and
+// replaced as a whole, never changed in place, by the Set* methods.
+type settings struct {
+	transform func(string) string /* Applied to messages before output */
+	escapeNL  bool                /* Escape newlines in messages */
+	journald  bool                /* Prefix messages with priorities */
+	seqOn     bool                /* Prefix messages with sequence numbers */
+	format    func(Record) []byte /* Custom formatter */
+	hostField string              /* host=hostname, from SetHostField */
+	pidField  string              /* pid=pid, from SetPIDField */
+	maxLen    int                 /* Maximum message length */
+	uptimeOn  bool                /* Prefix messages with uptime */
+	prefixFn  func() string       /* Dynamic prefix */
+	errStack  bool                /* Log errors' stack traces */
+	lockW     bool                /* Lock writers while writing */
+	term      *string             /* Line terminator, if not \n */
+	prefix    *string             /* From Apply, if not the logger's */
+	wrap      bool                /* Wrap lines to the terminal */
+	tz        *time.Location      /* From SetTimezone */
+	trim      string              /* From SetCallerTrim */
+	shortfile bool                /* Add log.Lshortfile, from -v -v -v */
+	start     time.Time           /* When New or Reset was called */
+}
+
+// noSettings are the settings of a LogSet whose settings haven't been set,
+// such as a zero-value LogSet.  They mustn't be changed.
+var noSettings settings
+
+/* conf returns l's settings, which mustn't be changed */
+func (l *LogSet) conf() *settings {
+	if s := l.cfg.Load(); nil != s {
+		return s
+	}
+	return &noSettings
+}
+
+/* prefixOf returns the prefix to put before messages logged with t */
+func (s *settings) prefixOf(t *log.Logger) string {
+	if nil != s.prefix {
+		return *s.prefix
+	}
+	return t.Prefix()
+}
+
+// set changes l's settings by calling f with a copy of them, which then
+// replaces them.  Messages being logged at the same time use either the old
+// settings or the new ones, never a mixture.
+func (l *LogSet) set(f func(s *settings)) {
+	l.cfgM.Lock()
+	defer l.cfgM.Unlock()
+	s := *l.conf()
+	f(&s)
+	l.cfg.Store(&s)
+}
@@ -0,0 +1,128 @@
+package easylogger
+
+/*
+ * signal.go
+ * Toggle debug logging with a signal
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// EnableFromSignal installs a handler which toggles the default LogSet's
+// debug logging each time sig is received.  See LogSet.EnableFromSignal.
+func EnableFromSignal(sig os.Signal) (uninstall func()) {
+	return def.EnableFromSignal(sig)
+}
+
+// EnableFromSignal installs a handler which toggles debug logging each time
+// sig is received.  If debug logging is off, the current settings are saved
+// and debug logging is turned on, as if LogDebug had been called.  If debug
+// logging is on, the settings saved by the previous signal are restored (or
+// all logging is turned off if there are none).  This allows a long-running
+// program to be debugged without a restart:
+//
+//    uninstall := ls.EnableFromSignal(syscall.SIGUSR1)
+//    defer uninstall()
+//
+// The returned function uninstalls the handler.  It is safe to call more than
+// once.
+//
+// The settings are not switched in step with messages being logged in other
+// goroutines.  A message logged at about the same time as the signal arrives
+// may be logged (or not) under either the old or the new settings.
+func (l *LogSet) EnableFromSignal(sig os.Signal) (uninstall func()) {
+	var (
+		ch   = make(chan os.Signal, 1)
+		done = make(chan struct{})
+		once sync.Once
+	)
+	signal.Notify(ch, sig)
+	go l.toggleOnSignal(ch, done)
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// toggleOnSignal toggles debug logging every time something's sent on ch,
+// until done is closed.
+func (l *LogSet) toggleOnSignal(ch <-chan os.Signal, done <-chan struct{}) {
+	var sv, sd, sc bool /* Saved verbose, debug, and changed */
+	for {
+		select {
+		case <-ch:
+		case <-done:
+			return
+		}
+		v, d, c := l.switches()
+		/* If we're debugging, go back to what we were doing */
+		if d && (v || !c) {
+			l.setSwitches(sv, sd, sc)
+			sv, sd, sc = false, false, false
+			continue
+		}
+		/* Otherwise, save the state and start debugging */
+		sv, sd, sc = v, d, c
+		l.LogDebug()
+	}
+}
+
+// RegisterShutdown installs a handler which, on SIGINT or SIGTERM, calls the
+// default LogSet's Close, so that buffered output isn't lost, and then
+// terminates the program by re-raising the signal, as would have happened
+// without the handler.  If the signal can't be re-raised, as on Windows, the
+// program exits with status 1.  Only the handler's own interest in the signal
+// is given up before it's re-raised, so if something else in the program has
+// asked for the signal with signal.Notify, it's delivered there instead and
+// the program keeps running.  This is meant for programs without a clean
+// shutdown path of their own:
+//
+//    func main() {
+//            defer easylogger.RegisterShutdown()()
+//            /* ... */
+//    }
+//
+// The returned function uninstalls the handler.  It is safe to call more than
+// once.
+func RegisterShutdown() (cancel func()) {
+	var (
+		ch   = make(chan os.Signal, 1)
+		done = make(chan struct{})
+		once sync.Once
+	)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		var sig os.Signal
+		select {
+		case sig = <-ch:
+		case <-done:
+			return
+		}
+		def.Close()
+		/* Let the signal do what it would've done, leaving other
+		handlers alone */
+		signal.Stop(ch)
+		p, err := os.FindProcess(os.Getpid())
+		if nil == err {
+			err = p.Signal(sig)
+		}
+		if nil != err {
+			os.Exit(1)
+		}
+	}()
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
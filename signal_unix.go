@@ -0,0 +1,21 @@
+//go:build unix
+
+package easylogger
+
+/*
+ * signal_unix.go
+ * Toggle debug logging with SIGUSR1
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import "syscall"
+
+// EnableFromSIGUSR1 toggles the default LogSet's debug logging every time
+// SIGUSR1 is received.  It is shorthand for
+// EnableFromSignal(syscall.SIGUSR1), and is only available on platforms with
+// SIGUSR1.
+func EnableFromSIGUSR1() (uninstall func()) {
+	return EnableFromSignal(syscall.SIGUSR1)
+}
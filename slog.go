@@ -0,0 +1,201 @@
+package easylogger
+
+/*
+ * slog.go
+ * Log slog records with a LogSet
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// slogHandler is a slog.Handler which logs with a LogSet.
+type slogHandler struct {
+	l     *LogSet
+	min   Level  /* Least severe level logged */
+	attrs string /* Formatted attributes from WithAttrs */
+	group string /* Prefix for attribute keys, from WithGroup */
+}
+
+// InstallAsSlogDefault causes records logged with slog's default logger to
+// be logged by the default LogSet.  See LogSet.InstallAsSlogDefault.
+func InstallAsSlogDefault(level Level) (restore func()) {
+	return def.InstallAsSlogDefault(level)
+}
+
+// InstallAsSlogDefault sets slog's default logger to one which logs records
+// with l, so that slog.Info and friends go through l's hooks, processors,
+// formatting, and so on.  The returned function puts back the previous
+// default logger.
+//
+// Levels are mapped as follows:
+//
+//    slog.LevelError and above     LevelNone (always logged, like panics)
+//    slog.LevelInfo to LevelWarn   LevelVerbose
+//    below slog.LevelInfo          LevelDebug
+//
+// and the other way, LevelNone is slog.LevelError, LevelVerbose is
+// slog.LevelInfo, and LevelDebug and LevelDebugOnly are slog.LevelDebug.  A
+// record is logged if l logs messages at its mapped level and the mapped
+// level is at least as severe as level, so InstallAsSlogDefault(LevelVerbose)
+// never logs slog.Debug records.  A record's attributes are added to the end
+// of the message as key=value, with group names and dots before the keys.
+//
+// As slog.SetDefault sends the standard logger's output to slog, if l itself
+// logs to the standard logger, by default or because it was given
+// log.Default with SetLogger, it's given a copy of the standard logger, as
+// with CaptureStdLog.  The returned function also puts back the standard
+// logger's output and flags.
+func (l *LogSet) InstallAsSlogDefault(level Level) (restore func()) {
+	std := log.Default()
+	out, prefix, flags := std.Writer(), std.Prefix(), std.Flags()
+	/* If we log to the standard logger, we'd loop forever */
+	var own, prev *log.Logger
+	if prev = l.logger.Load(); nil == prev || std == prev {
+		own = log.New(out, prefix, flags)
+		l.logger.Store(own)
+	}
+	old := slog.Default()
+	slog.SetDefault(slog.New(&slogHandler{l: l, min: severity(level)}))
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			slog.SetDefault(old)
+			std.SetOutput(out)
+			std.SetFlags(flags)
+			if nil != own {
+				l.logger.CompareAndSwap(own, prev)
+			}
+		})
+	}
+}
+
+// levelFromSlog returns the Level to which the slog level lv is mapped.
+func levelFromSlog(lv slog.Level) Level {
+	switch {
+	case slog.LevelError <= lv:
+		return LevelNone
+	case slog.LevelInfo <= lv:
+		return LevelVerbose
+	default:
+		return LevelDebug
+	}
+}
+
+// slogLevel returns the slog level to which level is mapped.
+func slogLevel(level Level) slog.Level {
+	switch level {
+	case LevelNone:
+		return slog.LevelError
+	case LevelVerbose:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
+
+// SetSlogLevelVar keeps lv in step with the default LogSet's level.  See
+// LogSet.SetSlogLevelVar.
+func SetSlogLevelVar(lv *slog.LevelVar) { def.SetSlogLevelVar(lv) }
+
+// SetSlogLevelVar sets lv to the slog level corresponding to l's level, and
+// sets it again whenever l's level changes, including by command-line
+// flags, so that a slog handler using lv logs the same messages as l:
+//
+//    var lv slog.LevelVar
+//    h := slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: &lv})
+//    slog.SetDefault(slog.New(h))
+//    ls.SetSlogLevelVar(&lv)
+//    ls.SetLevel(easylogger.LevelDebug) /* slog.Debug works now, too */
+//
+// LevelNone is slog.LevelError, LevelVerbose is slog.LevelInfo, and
+// LevelDebug is slog.LevelDebug, as with InstallAsSlogDefault, whose
+// handler follows l's level by itself.  As slog has no way to log debug
+// messages but not info messages, LevelDebugOnly is slog.LevelDebug as
+// well.  SetQuietWindow doesn't affect lv.  A nil lv stops updating the
+// previous one.
+func (l *LogSet) SetSlogLevelVar(lv *slog.LevelVar) {
+	l.sm.Lock()
+	defer l.sm.Unlock()
+	l.levelVar = lv
+	l.syncLevelVar()
+}
+
+/* syncLevelVar updates the LevelVar, if any.  l.sm must be held. */
+func (l *LogSet) syncLevelVar() {
+	if nil != l.levelVar {
+		l.levelVar.Set(slogLevel(l.Level()))
+	}
+}
+
+/* Enabled reports whether records at lv are logged */
+func (h *slogHandler) Enabled(_ context.Context, lv slog.Level) bool {
+	level := levelFromSlog(lv)
+	return level <= h.min && h.l.Enabled(level)
+}
+
+/* Handle logs r */
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	b.WriteString(r.Message)
+	b.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		appendAttr(&b, h.group, a)
+		return true
+	})
+	h.l.log(levelFromSlog(r.Level), "%s", b.String())
+	return nil
+}
+
+/* WithAttrs returns a handler which adds attrs to every record */
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	b.WriteString(h.attrs)
+	for _, a := range attrs {
+		appendAttr(&b, h.group, a)
+	}
+	n := *h
+	n.attrs = b.String()
+	return &n
+}
+
+/* WithGroup returns a handler which puts later attributes in group name */
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if "" == name {
+		return h
+	}
+	n := *h
+	n.group += name + "."
+	return &n
+}
+
+// appendAttr appends a space and a as key=value to b, with group before the
+// key.  The values of groups are appended one attribute at a time.
+func appendAttr(b *strings.Builder, group string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if slog.KindGroup == a.Value.Kind() {
+		if "" != a.Key {
+			group += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			appendAttr(b, group, ga)
+		}
+		return
+	}
+	v := a.Value.String()
+	if "" == v || strings.ContainsAny(v, " \t\n\"=") {
+		v = strconv.Quote(v)
+	}
+	b.WriteString(" " + group + a.Key + "=" + v)
+}
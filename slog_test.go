@@ -0,0 +1,42 @@
+package easylogger
+
+/*
+ * slog_test.go
+ * Tests for log/slog support
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestInstallAsSlogDefaultSetLoggerDefault(t *testing.T) {
+	var b bytes.Buffer
+	std := log.Default()
+	out, flags := std.Writer(), std.Flags()
+	defer func() {
+		std.SetOutput(out)
+		std.SetFlags(flags)
+	}()
+	std.SetOutput(&b)
+	std.SetFlags(0)
+
+	l := New()
+	l.SetLogger(log.Default())
+	restore := l.InstallAsSlogDefault(LevelVerbose)
+	slog.Error("boom", "code", 7)
+	restore()
+	if got := b.String(); !strings.Contains(got, "boom") ||
+		!strings.Contains(got, "code=7") {
+		t.Errorf("Got %q, want the error", got)
+	}
+	if log.Default() != l.logger.Load() {
+		t.Errorf("Logger not put back")
+	}
+}
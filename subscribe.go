@@ -0,0 +1,105 @@
+package easylogger
+
+/*
+ * subscribe.go
+ * Send logged messages to a channel
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import "sync"
+
+// subscriber receives Records from SubscribeBlocking.
+type subscriber struct {
+	m      sync.RWMutex  /* Read-held while sending */
+	ch     chan Record   /* Records go here */
+	done   chan struct{} /* Closed to unblock senders */
+	closed bool          /* ch is closed */
+}
+
+/* send sends r to s, blocking until it's received or s is unsubscribed */
+func (s *subscriber) send(r Record) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.ch <- r:
+	case <-s.done:
+	}
+}
+
+/* close unblocks senders and closes s.ch once they're done */
+func (s *subscriber) close() {
+	close(s.done)
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.closed = true
+	close(s.ch)
+}
+
+// SubscribeBlocking returns a channel which receives every message logged
+// by the default LogSet.  See LogSet.SubscribeBlocking.
+func SubscribeBlocking(buf int) (<-chan Record, func()) {
+	return def.SubscribeBlocking(buf)
+}
+
+// SubscribeBlocking returns a channel, with a buffer of buf, which receives
+// every message l logs, and a function to unsubscribe.  When the channel's
+// buffer is full, logging blocks until there's room, so no message is ever
+// missed.  This is meant for low-volume streams which must be complete, such
+// as audit logs.
+//
+// If the channel isn't read, logging stops, and so will any goroutine which
+// logs with l.  Use with care.  Unsubscribing unblocks anything waiting to
+// send to the channel, and closes the channel.  The function to unsubscribe
+// may be called more than once.
+func (l *LogSet) SubscribeBlocking(buf int) (<-chan Record, func()) {
+	s := &subscriber{
+		ch:   make(chan Record, buf),
+		done: make(chan struct{}),
+	}
+	l.changeSubs(func(subs []*subscriber) []*subscriber {
+		return append(subs, s)
+	})
+	var once sync.Once
+	return s.ch, func() {
+		once.Do(func() {
+			l.changeSubs(func(subs []*subscriber) []*subscriber {
+				for i, sub := range subs {
+					if sub == s {
+						return append(subs[:i], subs[i+1:]...)
+					}
+				}
+				return subs
+			})
+			s.close()
+		})
+	}
+}
+
+// changeSubs replaces l's subscribers with what f returns when passed a
+// copy of them.
+func (l *LogSet) changeSubs(f func([]*subscriber) []*subscriber) {
+	l.subM.Lock()
+	defer l.subM.Unlock()
+	var subs []*subscriber
+	if old := l.subs.Load(); nil != old {
+		subs = append(subs, *old...)
+	}
+	subs = f(subs)
+	l.subs.Store(&subs)
+}
+
+/* publish sends r to l's subscribers */
+func (l *LogSet) publish(r Record) {
+	subs := l.subs.Load()
+	if nil == subs {
+		return
+	}
+	for _, s := range *subs {
+		s.send(r)
+	}
+}
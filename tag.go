@@ -0,0 +1,63 @@
+package easylogger
+
+/*
+ * tag.go
+ * Tagged messages
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import "io"
+
+// DebugTagged logs a debugging message with tags using the default LogSet.
+// See LogSet.DebugTagged.
+func DebugTagged(tags []string, format string, args ...interface{}) {
+	def.DebugTagged(tags, format, args...)
+}
+
+// SetTagRoute causes messages from the default LogSet tagged with tag to
+// also be written to w.  See LogSet.SetTagRoute.
+func SetTagRoute(tag string, w io.Writer) { def.SetTagRoute(tag, w) }
+
+// DebugTagged logs a message, like Debug, tagged with tags.  The tags are
+// put before the message as tags=tag1,tag2, and the message is also written
+// to the writers set with SetTagRoute for any of its tags.
+//
+//    ls.SetTagRoute("audit", auditFile)
+//    /* ... */
+//    ls.DebugTagged([]string{"audit"}, "%v deleted %v", user, file)
+func (l *LogSet) DebugTagged(tags []string, format string,
+	args ...interface{}) {
+	l.check()
+	l.logTagged(LevelDebug, tags, format, args...)
+}
+
+// SetTagRoute causes messages tagged with tag to be written to w as well as
+// wherever they'd otherwise be written.  Messages are written to w with the
+// logger's prefix and flags, or with the function passed to SetFormatFunc.
+// A message is written to w once for each of its tags routed to w.  Only
+// messages which are logged are written to w; routing a tag doesn't turn on
+// debugging messages.  A nil w removes tag's route.
+func (l *LogSet) SetTagRoute(tag string, w io.Writer) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	if nil == w {
+		delete(l.routes, tag)
+		return
+	}
+	if nil == l.routes {
+		l.routes = make(map[string]io.Writer)
+	}
+	l.routes[tag] = w
+}
+
+// route writes r to the writers for its tags, according to s.  l.m must be
+// held.
+func (l *LogSet) route(s *settings, r Record) {
+	for _, tag := range r.Tags {
+		if w, ok := l.routes[tag]; ok {
+			l.writeTo(s, w, s.format, r)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package easylogger
+
+/*
+ * terminator.go
+ * Line terminators other than newlines
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"bytes"
+	"io"
+)
+
+// termWriter replaces the newline at the end of each write with term.
+type termWriter struct {
+	w    io.Writer
+	term string
+}
+
+// Write writes p to the underlying writer in a single call, with its
+// trailing newline, if any, replaced by the terminator.
+func (w termWriter) Write(p []byte) (int, error) {
+	line, ok := bytes.CutSuffix(p, []byte("\n"))
+	if !ok {
+		return w.w.Write(p)
+	}
+	/* Copy, so as not to write into the caller's buffer */
+	b := make([]byte, 0, len(line)+len(w.term))
+	b = append(append(b, line...), w.term...)
+	if _, err := w.w.Write(b); nil != err {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// SetLineTerminator sets what's put after each message from the default
+// LogSet.  See LogSet.SetLineTerminator.
+func SetLineTerminator(s string) { def.SetLineTerminator(s) }
+
+// SetLineTerminator causes s to be put after each message instead of a
+// newline, for example "\r\n" for Windows tools, or "" for a protocol which
+// frames messages itself.  The default is "\n".  It applies to messages
+// written through the logger, with SetJournald, or to the routes from
+// SetTagRoute.  The bytes returned by the function passed to SetFormatFunc,
+// such as BinaryFormat, are written as-is, so it should add its own
+// terminator, if any.  Messages sent to the Windows Event Log have no
+// terminator.
+func (l *LogSet) SetLineTerminator(s string) {
+	var term *string
+	if "\n" != s {
+		term = &s
+	}
+	l.set(func(s *settings) { s.term = term })
+}
+
+/* lineTerm returns the line terminator */
+func (s *settings) lineTerm() string {
+	if nil == s.term {
+		return "\n"
+	}
+	return *s.term
+}
@@ -0,0 +1,80 @@
+package easylogger
+
+/*
+ * terminator_test.go
+ * Tests for SetLineTerminator
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"bytes"
+	"testing"
+)
+
+/* terminators are the line terminators to test */
+var terminators = []string{"\n", "\r\n", "", "\x00", "\n\n", " | "}
+
+func TestLineTerminatorText(t *testing.T) {
+	for _, term := range terminators {
+		l, b := bufferSet()
+		l.SetLineTerminator(term)
+		l.Debug("one")
+		l.Debug("two\n") /* log adds no second newline */
+		if got, want := b.String(), "one"+term+"two"+term; got != want {
+			t.Errorf("Terminator %q: got %q, want %q", term, got, want)
+		}
+	}
+}
+
+func TestLineTerminatorJournald(t *testing.T) {
+	for _, term := range terminators {
+		l, b := bufferSet()
+		l.SetJournald(true)
+		l.SetLineTerminator(term)
+		l.Verbose("msg")
+		if got, want := b.String(), "<6>msg"+term; got != want {
+			t.Errorf("Terminator %q: got %q, want %q", term, got, want)
+		}
+	}
+}
+
+func TestLineTerminatorRoutesAndOutputs(t *testing.T) {
+	for _, term := range terminators {
+		var route, output bytes.Buffer
+		l, _ := bufferSet()
+		l.SetLineTerminator(term)
+		l.SetTagRoute("db", &route)
+		l.AddFormattedOutput(&output, nil)
+		l.DebugTagged([]string{"db"}, "msg")
+		want := "tags=db msg" + term
+		if got := route.String(); got != want {
+			t.Errorf("Terminator %q: route got %q, want %q", term, got, want)
+		}
+		if got := output.String(); got != want {
+			t.Errorf("Terminator %q: output got %q, want %q", term, got, want)
+		}
+	}
+}
+
+func TestLineTerminatorFormatFunc(t *testing.T) {
+	for _, term := range terminators {
+		l, b := bufferSet()
+		l.SetLineTerminator(term)
+		l.SetFormatFunc(BinaryFormat)
+		l.Debug("msg")
+		/* Written as-is, so no terminator */
+		r, err := DecodeRecord(b)
+		if nil != err {
+			t.Errorf("Terminator %q: DecodeRecord: %v", term, err)
+			continue
+		}
+		if "msg" != r.Message {
+			t.Errorf("Terminator %q: got message %q", term, r.Message)
+		}
+		if 0 != b.Len() {
+			t.Errorf("Terminator %q: %q left over", term, b.String())
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package easylogger
+
+/*
+ * testlogger.go
+ * Log to a test's log
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"log"
+	"strings"
+	"sync"
+)
+
+// TB is the subset of testing.TB used by NewTestLogger.  A *testing.T or
+// *testing.B may be used as a TB.
+type TB interface {
+	Cleanup(func())
+	Log(args ...interface{})
+}
+
+// tbWriter sends whatever's written to it to a test's log, until the test
+// has finished.
+type tbWriter struct {
+	m    sync.Mutex
+	tb   TB
+	done bool /* The test has finished */
+}
+
+/* Write logs p, less its trailing newline, with tb.Log */
+func (w *tbWriter) Write(p []byte) (int, error) {
+	w.m.Lock()
+	defer w.m.Unlock()
+	if !w.done {
+		w.tb.Log(strings.TrimSuffix(string(p), "\n"))
+	}
+	return len(p), nil
+}
+
+/* stop prevents further logging to w.tb */
+func (w *tbWriter) stop() {
+	w.m.Lock()
+	defer w.m.Unlock()
+	w.done = true
+}
+
+// NewTestLogger returns a new LogSet, with debug logging on, which logs with
+// tb.Log.  Messages are attributed to the test and only shown if it fails or
+// go test is run with -v.
+//
+//    func TestThing(t *testing.T) {
+//            ls := easylogger.NewTestLogger(t)
+//            thing := NewThing(ls)
+//            /* ... */
+//    }
+//
+// The testing package shows every message as coming from testlogger.go, as
+// that's where tb.Log is called, so each message starts with the file and
+// line from which it was really logged (log.Lshortfile), e.g.
+//
+//    testlogger.go:37: thing.go:42: Connecting to db
+//
+// Messages logged after the test has finished, e.g. from a goroutine which
+// outlived it, are discarded rather than causing a panic.
+func NewTestLogger(tb TB) *LogSet {
+	w := &tbWriter{tb: tb}
+	tb.Cleanup(w.stop)
+	l := New()
+	l.SetLogger(log.New(w, "", log.Lshortfile))
+	l.LogDebug()
+	return l
+}
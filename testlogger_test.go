@@ -0,0 +1,52 @@
+package easylogger
+
+/*
+ * testlogger_test.go
+ * Tests for NewTestLogger
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"fmt"
+	"testing"
+)
+
+// fakeTB is a TB which remembers what's logged and runs its cleanup
+// functions when told.
+type fakeTB struct {
+	logs     []string
+	cleanups []func()
+}
+
+/* Cleanup remembers f, for finish */
+func (tb *fakeTB) Cleanup(f func()) { tb.cleanups = append(tb.cleanups, f) }
+
+/* Log remembers what's logged */
+func (tb *fakeTB) Log(args ...interface{}) {
+	tb.logs = append(tb.logs, fmt.Sprint(args...))
+}
+
+/* finish runs the cleanup functions, as when a test finishes */
+func (tb *fakeTB) finish() {
+	for _, f := range tb.cleanups {
+		f()
+	}
+}
+
+func TestTestLoggerCaller(t *testing.T) {
+	var tb fakeTB
+	l := NewTestLogger(&tb)
+	l.Debug("message")
+	want := above() + ": message"
+	l.Verbose("verbose")
+	tb.finish()
+	l.Debug("after the test")
+	if 2 != len(tb.logs) {
+		t.Fatalf("Got %d messages %q, want 2", len(tb.logs), tb.logs)
+	}
+	if tb.logs[0] != want {
+		t.Errorf("Got %q, want %q", tb.logs[0], want)
+	}
+}
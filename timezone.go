@@ -0,0 +1,59 @@
+package easylogger
+
+/*
+ * timezone.go
+ * Timestamps in a chosen time zone
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"log"
+	"time"
+)
+
+// timeFlags are the log flags which put the time in messages.
+const timeFlags = log.Ldate | log.Ltime | log.Lmicroseconds
+
+// SetTimezone causes timestamps from the default LogSet to be in loc.  See
+// LogSet.SetTimezone.
+func SetTimezone(loc *time.Location) { def.SetTimezone(loc) }
+
+// SetTimezone causes timestamps to be in loc, regardless of the host's time
+// zone, so that everyone reading the logs sees the same times:
+//
+//    hq, err := time.LoadLocation("America/New_York")
+//    if nil != err {
+//            log.Fatalf("Loading time zone: %v", err)
+//    }
+//    ls.SetTimezone(hq)
+//
+// Timestamps look as the logger's flags say they should, and are put where
+// the logger would put them.  SetTimezone and SetUTC undo each other, so
+// whichever was called last wins.  A nil loc means local time, the default.
+// SetTimezone doesn't affect SetFormatFunc, which may use Record.Time as it
+// pleases.
+func (l *LogSet) SetTimezone(loc *time.Location) {
+	if nil == loc {
+		l.SetUTC(false)
+		return
+	}
+	l.set(func(s *settings) { s.tz = loc })
+}
+
+// timestamp returns t formatted as the log package would with flags,
+// including the trailing space.
+func timestamp(t time.Time, flags int) string {
+	var b []byte
+	if 0 != flags&log.Ldate {
+		b = t.AppendFormat(b, "2006/01/02 ")
+	}
+	switch {
+	case 0 != flags&log.Lmicroseconds:
+		b = t.AppendFormat(b, "15:04:05.000000 ")
+	case 0 != flags&log.Ltime:
+		b = t.AppendFormat(b, "15:04:05 ")
+	}
+	return string(b)
+}
@@ -0,0 +1,97 @@
+package easylogger
+
+/*
+ * verbosity.go
+ * -v, -v -v, and so on
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"flag"
+	"strconv"
+)
+
+// verbosityFlag is a counting flag.Value which sets a LogSet's level.
+type verbosityFlag struct {
+	l *LogSet
+	n int
+}
+
+/* String returns the current count */
+func (f *verbosityFlag) String() string {
+	if nil == f {
+		return "0"
+	}
+	return strconv.Itoa(f.n)
+}
+
+// Set increments the count if s is "true" (i.e. the flag was given without
+// a value), resets it if s is "false", or otherwise sets it to s, and sets
+// the level accordingly.
+func (f *verbosityFlag) Set(s string) error {
+	switch s {
+	case "true":
+		f.n++
+	case "false":
+		f.n = 0
+	default:
+		n, err := strconv.Atoi(s)
+		if nil != err {
+			return err
+		}
+		f.n = n
+	}
+	f.apply()
+	return nil
+}
+
+/* IsBoolFlag allows the flag to be given without a value */
+func (f *verbosityFlag) IsBoolFlag() bool { return true }
+
+/* apply sets the LogSet's level according to the count */
+func (f *verbosityFlag) apply() {
+	switch {
+	case 0 >= f.n:
+		f.l.LogNone()
+	case 1 == f.n:
+		f.l.LogVerbose()
+	default:
+		f.l.LogDebug()
+	}
+	f.l.set(func(s *settings) { s.shortfile = 3 <= f.n })
+}
+
+// RegisterVerbosityFlag adds a flag named name to fs which sets the default
+// LogSet's level by how many times it's given.  See
+// LogSet.RegisterVerbosityFlag.
+func RegisterVerbosityFlag(fs *flag.FlagSet, name string) {
+	def.RegisterVerbosityFlag(fs, name)
+}
+
+// RegisterVerbosityFlag adds a flag named name to fs which sets l's level by
+// how many times it's given, e.g.
+//
+//    easylogger.RegisterVerbosityFlag(flag.CommandLine, "v")
+//
+// and then
+//
+//    prog           Nothing logged (LevelNone)
+//    prog -v        Verbose messages logged (LevelVerbose)
+//    prog -v -v     Everything logged (LevelDebug)
+//    prog -v -v -v  Everything logged, with file:line (log.Lshortfile)
+//
+// The file and line are only added to l's messages; the logger's flags
+// aren't changed, and lowering the count takes them away again.  The count
+// may also be given as a value, as in -v=2.  The flag package doesn't allow
+// flags to be combined, so -vv won't work.  The level is set as the flags
+// are parsed, so calling SetLevel or one of the Log* functions afterwards
+// overrides the flag.
+func (l *LogSet) RegisterVerbosityFlag(fs *flag.FlagSet, name string) {
+	fs.Var(
+		&verbosityFlag{l: l},
+		name,
+		"Increase verbosity (may be repeated, or given a count)",
+	)
+}
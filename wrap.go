@@ -0,0 +1,84 @@
+package easylogger
+
+/*
+ * wrap.go
+ * Wrap long lines to fit the terminal
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// wrapIndent starts each continuation line made by wrapping.
+const wrapIndent = "    "
+
+// wrapWriter wraps each line written to it to fit in width columns.
+type wrapWriter struct {
+	w     io.Writer
+	width int
+}
+
+// Write writes p to the underlying writer in a single call, with lines
+// longer than the width broken, preferably at spaces, into continuation
+// lines indented with wrapIndent.
+func (w wrapWriter) Write(p []byte) (int, error) {
+	text, nl := bytes.CutSuffix(p, []byte("\n"))
+	var b strings.Builder
+	for i, line := range strings.Split(string(text), "\n") {
+		if 0 != i {
+			b.WriteByte('\n')
+		}
+		wrapLine(&b, line, w.width)
+	}
+	if nl {
+		b.WriteByte('\n')
+	}
+	if _, err := io.WriteString(w.w, b.String()); nil != err {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+/* wrapLine writes line to b, wrapped to fit in width columns */
+func wrapLine(b *strings.Builder, line string, width int) {
+	room := width /* Room on this line */
+	for room < utf8.RuneCountInString(line) {
+		/* Find the byte offset of the last rune which fits */
+		cut := 0
+		for n := 0; n < room; n++ {
+			_, size := utf8.DecodeRuneInString(line[cut:])
+			cut += size
+		}
+		/* Break at a space, if there's one not too far back */
+		if sp := strings.LastIndexByte(line[:cut], ' '); cut/2 < sp {
+			cut = sp + 1
+		}
+		b.WriteString(strings.TrimRight(line[:cut], " "))
+		b.WriteString("\n" + wrapIndent)
+		line = line[cut:]
+		room = width - len(wrapIndent)
+	}
+	b.WriteString(line)
+}
+
+// SetWrap causes long lines from the default LogSet to be wrapped to fit
+// the terminal.  See LogSet.SetWrap.
+func SetWrap(on bool) { def.SetWrap(on) }
+
+// SetWrap causes messages longer than the terminal is wide to be wrapped
+// onto indented continuation lines, if on is true, which is easier to read
+// when watching a program's output during development.  Wrapping only
+// happens if the logger writes directly to a terminal, and not for
+// SetFormatFunc, SetJournald, or the Windows Event Log.  The terminal's
+// width is checked for every message, so resizing the terminal takes effect
+// straight away.  On platforms other than Unix-like ones, SetWrap does
+// nothing.
+func (l *LogSet) SetWrap(on bool) {
+	l.set(func(s *settings) { s.wrap = on })
+}
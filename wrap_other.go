@@ -0,0 +1,17 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package easylogger
+
+/*
+ * wrap_other.go
+ * Terminal width is unknown
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import "io"
+
+// termWidth returns the width of the terminal to which w writes, or 0 if w
+// isn't a terminal.
+func termWidth(w io.Writer) int { return 0 }
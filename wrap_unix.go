@@ -0,0 +1,46 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package easylogger
+
+/*
+ * wrap_unix.go
+ * Terminal width, from ioctl
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// termWidth returns the width of the terminal to which w writes, or 0 if w
+// isn't a terminal.
+func termWidth(w io.Writer) int {
+	f, ok := w.(*os.File)
+	if !ok {
+		return 0
+	}
+	rc, err := f.SyscallConn()
+	if nil != err {
+		return 0
+	}
+	var (
+		ws    struct{ row, col, x, y uint16 }
+		errno syscall.Errno
+	)
+	if err := rc.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(
+			syscall.SYS_IOCTL,
+			fd,
+			uintptr(syscall.TIOCGWINSZ),
+			uintptr(unsafe.Pointer(&ws)),
+		)
+	}); nil != err || 0 != errno {
+		return 0
+	}
+	return int(ws.col)
+}
@@ -0,0 +1,121 @@
+package easylogger
+
+/*
+ * writer.go
+ * Log what's written to an io.Writer
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"sync"
+)
+
+// levelWriter logs each line written to it at a level.  Partial lines are
+// held until the rest of the line is written.
+type levelWriter struct {
+	m     sync.Mutex
+	l     *LogSet
+	level Level
+	buf   []byte /* Partial line */
+}
+
+/* Write logs each complete line in p, and holds on to the rest */
+func (w *levelWriter) Write(p []byte) (int, error) {
+	w.m.Lock()
+	defer w.m.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if -1 == i {
+			break
+		}
+		w.l.log(w.level, "%s", w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	/* Don't hang on to a big array for a small partial line */
+	if 0 == len(w.buf) {
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+/* Close logs whatever partial line is left */
+func (w *levelWriter) Close() error {
+	w.m.Lock()
+	defer w.m.Unlock()
+	if 0 != len(w.buf) {
+		w.l.log(w.level, "%s", w.buf)
+		w.buf = nil
+	}
+	return nil
+}
+
+// Writer returns an io.WriteCloser which logs each line written to it as a
+// message from the default LogSet.  See LogSet.Writer.
+func Writer(level Level) io.WriteCloser { return def.Writer(level) }
+
+// CaptureStdLog routes the standard logger's output through the default
+// LogSet.  See LogSet.CaptureStdLog.
+func CaptureStdLog(level Level) (restore func()) {
+	return def.CaptureStdLog(level)
+}
+
+// Writer returns an io.WriteCloser which logs each line written to it as a
+// message at the given level, which is only logged if messages at that level
+// are logged.  LevelDebugOnly is the same as LevelDebug, and LevelNone
+// messages are always logged.  This is handy for giving a LogSet to things
+// which want an io.Writer or *log.Logger.
+//
+// A line may be written a piece at a time; nothing is logged until its
+// newline is written.  Closing the writer logs any final line without a
+// newline.  The writer is safe for concurrent use, though lines written a
+// piece at a time from more than one goroutine will be jumbled.
+func (l *LogSet) Writer(level Level) io.WriteCloser {
+	return &levelWriter{l: l, level: level}
+}
+
+// CaptureStdLog causes messages logged with the standard logger (e.g. with
+// log.Printf, possibly by a third-party package) to be logged by l at the
+// given level, as with Writer.  The returned function puts the standard
+// logger's output, prefix, and flags back the way they were.
+//
+// If l itself logs to the standard logger, by default or because it was given
+// log.Default with SetLogger, it's given a copy of the standard logger while
+// the standard logger's output is captured.  The returned function puts back
+// whichever l had, unless SetLogger's been called in the meantime.
+//
+// As log.SetOutput affects the whole program, CaptureStdLog should be called
+// before anything's logged with the standard logger in other goroutines, and
+// the returned function after they're done.  Captures shouldn't overlap.
+func (l *LogSet) CaptureStdLog(level Level) (restore func()) {
+	std := log.Default()
+	out, prefix, flags := std.Writer(), std.Prefix(), std.Flags()
+	/* If we log to the standard logger, we'd loop forever */
+	var own, prev *log.Logger
+	if prev = l.logger.Load(); nil == prev || std == prev {
+		own = log.New(out, prefix, flags)
+		l.logger.Store(own)
+	}
+	/* We'll add the prefix and flags */
+	w := l.Writer(level)
+	std.SetOutput(w)
+	std.SetPrefix("")
+	std.SetFlags(0)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			std.SetOutput(out)
+			std.SetPrefix(prefix)
+			std.SetFlags(flags)
+			w.Close()
+			if nil != own {
+				l.logger.CompareAndSwap(own, prev)
+			}
+		})
+	}
+}
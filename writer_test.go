@@ -0,0 +1,105 @@
+package easylogger
+
+/*
+ * writer_test.go
+ * Tests for Writer
+ *
+ * Copyright (c) 2012 J. Stuart McMurray. All rights reserved.
+ * Use of this source code is governed by the license in easylogger.go.
+ */
+
+import (
+	"bytes"
+	"log"
+	"testing"
+	"time"
+)
+
+/* writeBytes writes s to l's Writer a byte at a time and closes it */
+func writeBytes(t *testing.T, l *LogSet, s string) {
+	t.Helper()
+	w := l.Writer(LevelDebug)
+	for i := 0; i < len(s); i++ {
+		if n, err := w.Write([]byte{s[i]}); nil != err || 1 != n {
+			t.Fatalf("Write %d: wrote %d bytes, err %v", i, n, err)
+		}
+	}
+	if err := w.Close(); nil != err {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestWriterByteAtATime(t *testing.T) {
+	for _, c := range []struct {
+		in   string
+		want string
+	}{
+		{"one line\n", "seq=1 one line\n"},
+		{"first\nsecond\n", "seq=1 first\nseq=2 second\n"},
+		{"no newline", "seq=1 no newline\n"},
+		{"complete\npartial", "seq=1 complete\nseq=2 partial\n"},
+		{"\n", "seq=1 \n"},
+		{"", ""},
+		{"héllo, wörld\n", "seq=1 héllo, wörld\n"},
+	} {
+		l, b := bufferSet()
+		l.SetSequence(true) /* One number per message */
+		writeBytes(t, l, c.in)
+		if got := b.String(); got != c.want {
+			t.Errorf("Wrote %q: got %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestWriterNothingBeforeNewline(t *testing.T) {
+	l, b := bufferSet()
+	w := l.Writer(LevelDebug)
+	for _, c := range []byte("partial") {
+		w.Write([]byte{c})
+		if 0 != b.Len() {
+			t.Fatalf("Logged %q before newline", b.String())
+		}
+	}
+	w.Write([]byte{'\n'})
+	if got, want := b.String(), "partial\n"; got != want {
+		t.Errorf("Got %q, want %q", got, want)
+	}
+	/* Nothing left for Close */
+	w.Close()
+	if got, want := b.String(), "partial\n"; got != want {
+		t.Errorf("After Close: got %q, want %q", got, want)
+	}
+}
+
+func TestCaptureStdLogSetLoggerDefault(t *testing.T) {
+	var b bytes.Buffer
+	std := log.Default()
+	out, flags := std.Writer(), std.Flags()
+	std.SetOutput(&b)
+	std.SetFlags(0)
+
+	l := New()
+	l.SetLogger(log.Default())
+	l.LogVerbose()
+	restore := l.CaptureStdLog(LevelVerbose)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		log.Print("captured")
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		/* The standard logger's stuck, so can't be put back */
+		t.Fatalf("log.Print deadlocked")
+	}
+	restore()
+	std.SetOutput(out)
+	std.SetFlags(flags)
+	if got, want := b.String(), "captured\n"; got != want {
+		t.Errorf("Got %q, want %q", got, want)
+	}
+	if log.Default() != l.logger.Load() {
+		t.Errorf("Logger not put back")
+	}
+}